@@ -8,86 +8,109 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var applyProfile string
+var (
+	applyProfile string
+	applyPlan    bool
+	applyJobs    int
+	applyOutput  string
+)
 
 var applyCmd = &cobra.Command{
 	Use:   "apply [owner/repo]",
 	Short: "Apply a profile to an existing repo",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		nwo := args[0]
-
-		if err := config.ValidateNWO(nwo); err != nil {
+		if err := checkOutputFormat(applyOutput); err != nil {
 			return err
 		}
-
-		cfg, err := loadConfig()
-		if err != nil {
-			return err
+		if applyOutput == "" {
+			return runApply(args[0], nil)
 		}
+		result := &runResult{NWO: args[0]}
+		return result.finish(applyOutput, runApply(args[0], result))
+	},
+}
 
-		profileName := applyProfile
-		if profileName == "" {
-			profileName = cfg.DefaultProfile
-		}
-		profile, ok := cfg.Profiles[profileName]
-		if !ok {
-			return fmt.Errorf("profile %q not found", profileName)
-		}
-		if err := config.ValidateProfile(profileName, profile); err != nil {
-			return err
-		}
+// runApply applies the profile. With a result, progress is collected into
+// it instead of printed.
+func runApply(nwo string, result *runResult) error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return invalid(err)
+	}
 
-		client := ghclient.NewClient()
-		if err := client.CheckInstalled(); err != nil {
+	cfg, err := loadConfig()
+	if err != nil {
+		return invalid(err)
+	}
+
+	profileName := applyProfile
+	if profileName == "" {
+		profileName = cfg.DefaultProfile
+	}
+	profile, ok := cfg.Profiles[profileName]
+	if !ok {
+		return invalid(fmt.Errorf("profile %q not found", profileName))
+	}
+	if err := config.ValidateProfile(profileName, profile); err != nil {
+		return invalid(err)
+	}
+
+	opts := ghclient.CreateOpts{
+		Profile:     profile,
+		Concurrency: applyJobs,
+	}
+	if result != nil {
+		if result.Profile, err = resolvedProfile(profileName, profile); err != nil {
 			return err
 		}
-
-		var failures int
-		progress := func(name string, err error) {
-			if err == nil {
-				fmt.Printf("  ✓ %s\n", name)
-			} else {
-				fmt.Printf("  ✗ %s: %s\n", name, err.Error())
-				failures++
-			}
+	}
+	if applyPlan {
+		if result != nil {
+			result.Plan = ghclient.ApplyPlan(opts)
+			return nil
 		}
+		printPlan(ghclient.ApplyPlan(opts))
+		return nil
+	}
 
-		fmt.Printf("Applying profile %q to %s...\n", profileName, nwo)
+	client := ghclient.NewClient()
+	if err := client.CheckInstalled(); err != nil {
+		return err
+	}
 
-		// Apply settings
-		settings, err := ghclient.SettingsFromRepoSettings(profile.Settings)
-		if err != nil {
-			progress("Applied repo settings", err)
-		} else {
-			err = client.UpdateSettings(nwo, settings)
-			progress("Applied repo settings", err)
+	if result != nil {
+		result.collect(&opts)
+		if err := client.ApplyProfile(nwo, opts); err != nil {
+			return partial(err)
 		}
+		return nil
+	}
 
-		// Sync labels
-		deleted, created, labelErrs := client.SyncLabels(nwo, profile.Labels)
-		var labelErr error
-		if len(labelErrs) > 0 {
-			labelErr = fmt.Errorf("%d label errors", len(labelErrs))
+	var failures int
+	opts.OnProgress = func(s ghclient.StepStatus) {
+		printStep(s)
+		if !s.Success && !s.Skipped {
+			failures++
 		}
-		progress(fmt.Sprintf("Synced labels (-%d/+%d)", deleted, created), labelErr)
+	}
 
-		// Branch protection
-		if profile.BranchProtection.Branch != "" {
-			err = client.SetBranchProtection(nwo, profile.BranchProtection)
-			progress("Set branch protection", err)
-		}
+	fmt.Printf("Applying profile %q to %s...\n", profileName, nwo)
+	if err := client.ApplyProfile(nwo, opts); err != nil && failures == 0 {
+		return partial(err)
+	}
 
-		if failures > 0 {
-			fmt.Printf("\nCompleted with %d error(s).\n", failures)
-			return fmt.Errorf("%d step(s) failed", failures)
-		}
-		fmt.Println("\nDone!")
-		return nil
-	},
+	if failures > 0 {
+		fmt.Printf("\nCompleted with %d error(s).\n", failures)
+		return partial(fmt.Errorf("%d step(s) failed", failures))
+	}
+	fmt.Println("\nDone!")
+	return nil
 }
 
 func init() {
 	applyCmd.Flags().StringVarP(&applyProfile, "profile", "p", "", "Profile to apply (default: from config)")
+	applyCmd.Flags().BoolVar(&applyPlan, "plan", false, "Print the steps and their dependencies without running them")
+	applyCmd.Flags().IntVar(&applyJobs, "concurrency", 0, "Steps to run at once (default 4)")
+	addOutputFlag(applyCmd, &applyOutput)
 	rootCmd.AddCommand(applyCmd)
 }
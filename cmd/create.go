@@ -1,84 +1,268 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/ggfevans/gh-mint/internal/config"
 	ghclient "github.com/ggfevans/gh-mint/internal/github"
+	"github.com/ggfevans/gh-mint/internal/journal"
 	"github.com/spf13/cobra"
 )
 
 var (
-	createProfile string
-	createPublic  bool
-	createPrivate bool
+	createProfile  string
+	createPublic   bool
+	createPrivate  bool
+	createTopics   []string
+	createHomepage string
+	createRollback bool
+	createPlan     bool
+	createJobs     int
+	createOutput   string
+	createAdopt    bool
+	createTemplate string
+	createClone    string
 )
 
+// cloneIntoName is --clone's value when it is given without one.
+const cloneIntoName = "<name>"
+
 var createCmd = &cobra.Command{
 	Use:   "create [name]",
 	Short: "Create a new repo with profile defaults",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
-
-		if err := config.ValidateRepoName(name); err != nil {
+		if err := checkOutputFormat(createOutput); err != nil {
 			return err
 		}
-
-		cfg, err := loadConfig()
-		if err != nil {
-			return err
+		if createOutput == "" {
+			return runCreate(cmd, args[0], nil)
 		}
+		result := &runResult{}
+		return result.finish(createOutput, runCreate(cmd, args[0], result))
+	},
+}
 
-		profileName := createProfile
-		if profileName == "" {
-			profileName = cfg.DefaultProfile
-		}
-		profile, ok := cfg.Profiles[profileName]
-		if !ok {
-			return fmt.Errorf("profile %q not found", profileName)
-		}
-		if err := config.ValidateProfile(profileName, profile); err != nil {
-			return err
+// runCreate creates the repo. With a result, progress is collected into it
+// instead of printed.
+func runCreate(cmd *cobra.Command, name string, result *runResult) error {
+	if err := config.ValidateRepoName(name); err != nil {
+		return invalid(err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return invalid(err)
+	}
+
+	profileName := createProfile
+	if profileName == "" {
+		profileName = cfg.DefaultProfile
+	}
+	profile, ok := cfg.Profiles[profileName]
+	if !ok {
+		return invalid(fmt.Errorf("profile %q not found", profileName))
+	}
+	if err := config.ValidateProfile(profileName, profile); err != nil {
+		return invalid(err)
+	}
+
+	desc := cmd.Flag("description").Value.String()
+	if err := config.ValidateDescription(desc); err != nil {
+		return invalid(err)
+	}
+
+	if err := config.ValidateTopics(createTopics); err != nil {
+		return invalid(err)
+	}
+	if err := config.ValidateHomepageTemplate(createHomepage); err != nil {
+		return invalid(err)
+	}
+	if createTemplate != "" {
+		if err := config.ValidateNWO(createTemplate); err != nil {
+			return invalid(fmt.Errorf("--template: %w", err))
 		}
+	}
+
+	cloneDir, err := resolveCloneDir(createClone, name)
+	if err != nil {
+		return invalid(err)
+	}
+
+	public := createPublic
+	if createPrivate {
+		public = false
+	}
 
-		desc := cmd.Flag("description").Value.String()
-		if err := config.ValidateDescription(desc); err != nil {
+	opts := ghclient.CreateOpts{
+		Name:              name,
+		Description:       desc,
+		Public:            public,
+		Profile:           profile,
+		Owner:             cfg.DefaultOwner,
+		Topics:            createTopics,
+		Homepage:          createHomepage,
+		Template:          createTemplate,
+		OnProgress:        printStep,
+		OnPrivateKey:      printPrivateKey,
+		RollbackOnFailure: createRollback,
+		Adopt:             createAdopt,
+		Concurrency:       createJobs,
+		CloneDir:          cloneDir,
+	}
+	// Structured output keeps stdout for the result; private keys and
+	// warnings go to stderr.
+	warn := os.Stdout
+	if result != nil {
+		if result.Profile, err = resolvedProfile(profileName, profile); err != nil {
 			return err
 		}
+		result.collect(&opts)
+		opts.OnPrivateKey = func(title string, key []byte) {
+			fmt.Fprintf(os.Stderr, "Private key for deploy key %q (shown once, save it now):\n\n%s\n", title, key)
+		}
+		warn = os.Stderr
+	}
 
-		public := createPublic
-		if createPrivate {
-			public = false
+	if createPlan {
+		var plan []ghclient.PlanStep
+		if len(profile.Hooks.PreCreate) > 0 {
+			plan = append(plan, ghclient.PlanStep{ID: "pre-create", Name: "Ran pre_create hooks"})
+		}
+		plan = append(plan, ghclient.PlanStep{ID: "create", Name: "Created repository"})
+		plan = append(plan, ghclient.CreatePlan(opts)...)
+		if result != nil {
+			result.Plan = plan
+			return nil
 		}
+		printPlan(plan)
+		return nil
+	}
 
-		client := ghclient.NewClient()
-		if err := client.CheckInstalled(); err != nil {
-			return err
+	client := ghclient.NewClient()
+	if err := client.CheckInstalled(); err != nil {
+		return err
+	}
+
+	run, err := newRun(profileName, opts)
+	if err != nil {
+		fmt.Fprintf(warn, "Warning: %v; this run can't be resumed\n", err)
+	}
+	opts.Journal = run
+
+	url, err := client.CreateWithDefaults(opts)
+	var exists *ghclient.RepoExistsError
+	if errors.As(err, &exists) {
+		if result != nil {
+			result.NWO = exists.NWO
+		}
+		return fmt.Errorf("%w; run again with --adopt to apply the profile to it", err)
+	}
+	if result != nil {
+		result.URL = url
+		result.NWO = nwoFromURL(url)
+		if run != nil && run.NWO != "" {
+			result.NWO = run.NWO
+		}
+	}
+	if err != nil {
+		if run != nil && run.Succeeded("create") && !run.Succeeded("rollback") {
+			if result != nil {
+				result.RunID = run.ID
+			} else {
+				fmt.Printf("\nResume with: gh mint resume %s\n", run.ID)
+			}
+		}
+		// A URL means the repo exists; without one nothing was left behind.
+		if url != "" {
+			return partial(err)
 		}
+		return err
+	}
+	if result == nil {
+		fmt.Printf("\nDone! %s\n", url)
+	}
+	return nil
+}
 
-		opts := ghclient.CreateOpts{
-			Name:        name,
-			Description: desc,
-			Public:      public,
-			Profile:     profile,
-			Owner:       cfg.DefaultOwner,
-			OnProgress: func(s ghclient.StepStatus) {
-				if s.Success {
-					fmt.Printf("  ✓ %s\n", s.Name)
-				} else {
-					fmt.Printf("  ✗ %s: %s\n", s.Name, s.Message)
-				}
-			},
+func printStep(s ghclient.StepStatus) {
+	switch {
+	case s.Success:
+		fmt.Printf("  ✓ %s\n", s.Name)
+	case s.Skipped:
+		fmt.Printf("  - %s: %s\n", s.Name, s.Message)
+	default:
+		fmt.Printf("  ✗ %s: %s\n", s.Name, s.Message)
+	}
+	if out := strings.TrimRight(s.Output, "\n"); out != "" {
+		for _, line := range strings.Split(out, "\n") {
+			fmt.Printf("      %s\n", line)
 		}
+	}
+}
 
-		url, err := client.CreateWithDefaults(opts)
-		if err != nil {
-			return err
+// printPlan lists steps in run order with the steps each one waits for.
+func printPlan(plan []ghclient.PlanStep) {
+	for _, s := range plan {
+		if len(s.DependsOn) > 0 {
+			fmt.Printf("  %-18s %s (after %s)\n", s.ID, s.Name, strings.Join(s.DependsOn, ", "))
+		} else {
+			fmt.Printf("  %-18s %s\n", s.ID, s.Name)
 		}
-		fmt.Printf("\nDone! %s\n", url)
-		return nil
-	},
+	}
+}
+
+func printPrivateKey(title string, key []byte) {
+	fmt.Printf("\n  Private key for deploy key %q (shown once, save it now):\n\n%s\n", title, key)
+}
+
+// newRun starts a journal for a create run so it can be resumed.
+func newRun(profileName string, opts ghclient.CreateOpts) (*journal.Run, error) {
+	dir, err := journal.Dir()
+	if err != nil {
+		return nil, err
+	}
+	run, err := journal.New(dir)
+	if err != nil {
+		return nil, err
+	}
+	run.Profile = profileName
+	run.Name = opts.Name
+	run.Owner = opts.Owner
+	run.Description = opts.Description
+	run.Public = opts.Public
+	run.Topics = opts.Topics
+	run.Homepage = opts.Homepage
+	run.Template = opts.Template
+	run.CloneDir = opts.CloneDir
+	return run, nil
+}
+
+// resolveCloneDir returns the absolute directory for --clone, or empty if
+// it wasn't given. It must not exist yet, or be empty.
+func resolveCloneDir(flag, name string) (string, error) {
+	switch flag {
+	case "":
+		return "", nil
+	case cloneIntoName:
+		flag = name
+	}
+	dir, err := filepath.Abs(flag)
+	if err != nil {
+		return "", fmt.Errorf("--clone: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("--clone: %s already exists and is not empty", dir)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("--clone: %w", err)
+	}
+	return dir, nil
 }
 
 func init() {
@@ -87,5 +271,16 @@ func init() {
 	createCmd.Flags().BoolVar(&createPrivate, "private", false, "Create private repo")
 	createCmd.MarkFlagsMutuallyExclusive("public", "private")
 	createCmd.Flags().String("description", "", "Repo description")
+	createCmd.Flags().StringArrayVar(&createTopics, "topic", nil, "Topic to add (repeatable, merged with profile topics)")
+	createCmd.Flags().StringVar(&createHomepage, "homepage", "", "Homepage URL (overrides profile homepage)")
+	createCmd.Flags().StringVar(&createTemplate, "template", "", "Template repository to generate from, as owner/name (overrides profile template_repo)")
+	createCmd.Flags().BoolVar(&createRollback, "rollback-on-failure", false, "Delete the new repo if any later step fails")
+	createCmd.Flags().BoolVar(&createPlan, "plan", false, "Print the steps and their dependencies without running them")
+	createCmd.Flags().IntVar(&createJobs, "concurrency", 0, "Steps to run at once (default 4)")
+	createCmd.Flags().BoolVar(&createAdopt, "adopt", false, "If the repo already exists, apply the profile to it instead")
+	createCmd.MarkFlagsMutuallyExclusive("adopt", "rollback-on-failure")
+	createCmd.Flags().StringVar(&createClone, "clone", "", "Clone the repo into this directory and apply the profile's local settings")
+	createCmd.Flags().Lookup("clone").NoOptDefVal = cloneIntoName
+	addOutputFlag(createCmd, &createOutput)
 	rootCmd.AddCommand(createCmd)
 }
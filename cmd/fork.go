@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	ghclient "github.com/ggfevans/gh-mint/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var (
+	forkProfile string
+	forkName    string
+	forkPlan    bool
+	forkJobs    int
+	forkOutput  string
+)
+
+var forkCmd = &cobra.Command{
+	Use:   "fork [upstream/repo]",
+	Short: "Fork a repo and apply a profile to the fork",
+	Long:  "Forks a repo into your account, or default_owner if set, and applies the profile to the fork: settings, labels, and the other steps of apply. With fork.sync_upstream, also adds a scheduled workflow that keeps the fork in sync.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOutputFormat(forkOutput); err != nil {
+			return err
+		}
+		if forkOutput == "" {
+			return runFork(args[0], nil)
+		}
+		result := &runResult{}
+		return result.finish(forkOutput, runFork(args[0], result))
+	},
+}
+
+// runFork forks upstream and applies the profile. With a result, progress
+// is collected into it instead of printed.
+func runFork(upstream string, result *runResult) error {
+	if err := config.ValidateNWO(upstream); err != nil {
+		return invalid(err)
+	}
+	if forkName != "" {
+		if err := config.ValidateRepoName(forkName); err != nil {
+			return invalid(err)
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return invalid(err)
+	}
+
+	profileName := forkProfile
+	if profileName == "" {
+		profileName = cfg.DefaultProfile
+	}
+	profile, ok := cfg.Profiles[profileName]
+	if !ok {
+		return invalid(fmt.Errorf("profile %q not found", profileName))
+	}
+	if err := config.ValidateProfile(profileName, profile); err != nil {
+		return invalid(err)
+	}
+
+	opts := ghclient.CreateOpts{
+		Name:        forkName,
+		Profile:     profile,
+		Owner:       cfg.DefaultOwner,
+		OnProgress:  printStep,
+		Concurrency: forkJobs,
+	}
+	// Structured output keeps stdout for the result; warnings go to
+	// stderr.
+	warn := os.Stdout
+	if result != nil {
+		if result.Profile, err = resolvedProfile(profileName, profile); err != nil {
+			return err
+		}
+		result.collect(&opts)
+		warn = os.Stderr
+	}
+	// A fork has no working tree to run post_create in, and GitHub, not
+	// gh-mint, decides whether it is made.
+	if len(profile.Hooks.PreCreate) > 0 || len(profile.Hooks.PostCreate) > 0 {
+		fmt.Fprintf(warn, "Warning: fork doesn't run profile %q's pre_create or post_create hooks\n", profileName)
+	}
+
+	if forkPlan {
+		plan := append([]ghclient.PlanStep{{ID: "fork", Name: "Forked " + upstream}}, ghclient.ForkPlan(opts)...)
+		if result != nil {
+			result.Plan = plan
+			return nil
+		}
+		printPlan(plan)
+		return nil
+	}
+
+	client := ghclient.NewClient()
+	if err := client.CheckInstalled(); err != nil {
+		return err
+	}
+
+	if result == nil {
+		fmt.Printf("Forking %s with profile %q...\n", upstream, profileName)
+	}
+	url, err := client.ForkWithDefaults(upstream, opts)
+	if result != nil {
+		result.URL = url
+		result.NWO = nwoFromURL(url)
+	}
+	if err != nil {
+		if url != "" {
+			return partial(err)
+		}
+		return err
+	}
+	if result == nil {
+		fmt.Printf("\nDone! %s\n", url)
+	}
+	return nil
+}
+
+func init() {
+	forkCmd.Flags().StringVarP(&forkProfile, "profile", "p", "", "Profile to apply (default: from config)")
+	forkCmd.Flags().StringVar(&forkName, "name", "", "Name for the fork (default: the upstream name)")
+	forkCmd.Flags().BoolVar(&forkPlan, "plan", false, "Print the steps and their dependencies without running them")
+	forkCmd.Flags().IntVar(&forkJobs, "concurrency", 0, "Steps to run at once (default 4)")
+	addOutputFlag(forkCmd, &forkOutput)
+	rootCmd.AddCommand(forkCmd)
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	ghclient "github.com/ggfevans/gh-mint/internal/github"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Exit codes. They are part of the CLI's interface; see the README.
+const (
+	exitError      = 1 // any other failure
+	exitValidation = 2 // bad flags, config, or profile; nothing was changed
+	exitAuth       = 3 // gh is missing, not logged in, or lacks a scope
+	exitPartial    = 4 // the repo exists but some steps failed
+	exitRepoExists = 5 // create found the repo already exists
+)
+
+// codedError carries the exit code for an error returned from a command.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+func invalid(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{exitValidation, err}
+}
+
+func partial(err error) error {
+	return &codedError{exitPartial, err}
+}
+
+// ExitCode returns the process exit code for an error from Execute.
+func ExitCode(err error) int {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	var auth *ghclient.AuthError
+	if errors.As(err, &auth) {
+		return exitAuth
+	}
+	var exists *ghclient.RepoExistsError
+	if errors.As(err, &exists) {
+		return exitRepoExists
+	}
+	return exitError
+}
+
+// addOutputFlag registers --output on a command.
+func addOutputFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVarP(format, "output", "o", "", "Print a structured result instead of text: json or yaml")
+}
+
+func checkOutputFormat(format string) error {
+	switch format {
+	case "", "json", "yaml":
+		return nil
+	}
+	return invalid(fmt.Errorf("invalid --output %q: must be json or yaml", format))
+}
+
+// writeOutput prints v to stdout as JSON or YAML.
+func writeOutput(format string, v interface{}) error {
+	return encodeOutput(os.Stdout, format, v)
+}
+
+func encodeOutput(w io.Writer, format string, v interface{}) error {
+	if format == "yaml" {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// profileResult is a profile as the config resolved it, keyed the same way
+// as the config file.
+type profileResult struct {
+	Name   string                 `json:"name" yaml:"name"`
+	Config map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+func resolvedProfile(name string, p config.Profile) (*profileResult, error) {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("encoding profile %q: %w", name, err)
+	}
+	var m map[string]interface{}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("encoding profile %q: %w", name, err)
+	}
+	return &profileResult{Name: name, Config: m}, nil
+}
+
+type stepResult struct {
+	Name       string `json:"name" yaml:"name"`
+	Status     string `json:"status" yaml:"status"` // succeeded, failed, or skipped
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms" yaml:"duration_ms"`
+	Output     string `json:"output,omitempty" yaml:"output,omitempty"` // from a hook command
+}
+
+func newStepResult(s ghclient.StepStatus) stepResult {
+	r := stepResult{Name: s.Name, Status: "succeeded", Error: s.Message, DurationMS: s.Duration.Milliseconds(), Output: s.Output}
+	switch {
+	case s.Skipped:
+		r.Status = "skipped"
+	case !s.Success:
+		r.Status = "failed"
+	}
+	return r
+}
+
+// runResult is the structured result of the commands that run a profile.
+type runResult struct {
+	URL      string              `json:"url,omitempty" yaml:"url,omitempty"`
+	NWO      string              `json:"nwo,omitempty" yaml:"nwo,omitempty"`
+	RunID    string              `json:"run_id,omitempty" yaml:"run_id,omitempty"`
+	Profile  *profileResult      `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Plan     []ghclient.PlanStep `json:"plan,omitempty" yaml:"plan,omitempty"`
+	Steps    []stepResult        `json:"steps" yaml:"steps"`
+	Labels   *ghclient.LabelDiff `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Error    string              `json:"error,omitempty" yaml:"error,omitempty"`
+	ExitCode int                 `json:"exit_code" yaml:"exit_code"`
+}
+
+// collect wires opts to record steps and the label diff into the result
+// instead of printing them.
+func (r *runResult) collect(opts *ghclient.CreateOpts) {
+	opts.OnProgress = func(s ghclient.StepStatus) {
+		r.Steps = append(r.Steps, newStepResult(s))
+	}
+	opts.OnLabelDiff = func(d ghclient.LabelDiff) {
+		r.Labels = &d
+	}
+}
+
+// finish prints the result with err's message and exit code, and returns
+// err so the command still exits with that code.
+func (r *runResult) finish(format string, err error) error {
+	if r.Steps == nil {
+		r.Steps = []stepResult{}
+	}
+	if err != nil {
+		r.Error = err.Error()
+		r.ExitCode = ExitCode(err)
+	}
+	if werr := writeOutput(format, r); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// nwoFromURL returns owner/name from a repo's GitHub URL.
+func nwoFromURL(url string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(url, "https://github.com/"), "/")
+}
+
+type profileSummary struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Labels      int    `json:"labels" yaml:"labels"`
+	Default     bool   `json:"default" yaml:"default"`
+}
+
+func profileSummaries(cfg *config.Config) []profileSummary {
+	names := slices.Sorted(maps.Keys(cfg.Profiles))
+	out := make([]profileSummary, 0, len(names))
+	for _, name := range names {
+		p := cfg.Profiles[name]
+		out = append(out, profileSummary{
+			Name:        name,
+			Description: p.Description,
+			Labels:      len(p.Labels.Items),
+			Default:     name == cfg.DefaultProfile,
+		})
+	}
+	return out
+}
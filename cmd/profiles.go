@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/ggfevans/gh-mint/internal/config"
@@ -15,13 +18,24 @@ var profilesCmd = &cobra.Command{
 	Short: "Manage profiles",
 }
 
+var (
+	profilesListOutput string
+	profilesShowOutput string
+)
+
 var profilesListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available profiles",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOutputFormat(profilesListOutput); err != nil {
+			return err
+		}
 		cfg, err := loadConfig()
 		if err != nil {
-			return err
+			return invalid(err)
+		}
+		if profilesListOutput != "" {
+			return writeOutput(profilesListOutput, profileSummaries(cfg))
 		}
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 		fmt.Fprintln(w, "NAME\tDESCRIPTION\tLABELS\tDEFAULT")
@@ -41,17 +55,34 @@ var profilesShowCmd = &cobra.Command{
 	Short: "Show profile details",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOutputFormat(profilesShowOutput); err != nil {
+			return err
+		}
 		cfg, err := loadConfig()
 		if err != nil {
-			return err
+			return invalid(err)
 		}
 		name := args[0]
 		p, ok := cfg.Profiles[name]
 		if !ok {
-			return fmt.Errorf("profile %q not found", name)
+			return invalid(fmt.Errorf("profile %q not found", name))
+		}
+		if profilesShowOutput != "" {
+			resolved, err := resolvedProfile(name, p)
+			if err != nil {
+				return err
+			}
+			return writeOutput(profilesShowOutput, resolved)
 		}
 		fmt.Printf("Profile: %s\n", name)
-		fmt.Printf("Description: %s\n\n", p.Description)
+		fmt.Printf("Description: %s\n", p.Description)
+		if p.TemplateRepo != "" {
+			fmt.Printf("Template repo: %s\n", p.TemplateRepo)
+		}
+		if p.Fork.SyncUpstream {
+			fmt.Println("Fork: sync upstream daily")
+		}
+		fmt.Println()
 
 		fmt.Println("Settings:")
 		printBoolSetting := func(label string, v *bool) {
@@ -59,12 +90,28 @@ var profilesShowCmd = &cobra.Command{
 				fmt.Printf("  %s: %v\n", label, *v)
 			}
 		}
+		printStringSetting := func(label string, v string) {
+			if v != "" {
+				fmt.Printf("  %s: %s\n", label, v)
+			}
+		}
+		printBoolSetting("Issues", p.Settings.HasIssues)
 		printBoolSetting("Wiki", p.Settings.HasWiki)
 		printBoolSetting("Projects", p.Settings.HasProjects)
+		printBoolSetting("Discussions", p.Settings.HasDiscussions)
+		printBoolSetting("Template", p.Settings.IsTemplate)
+		printBoolSetting("Allow forking", p.Settings.AllowForking)
 		printBoolSetting("Delete branch on merge", p.Settings.DeleteBranchOnMerge)
+		printBoolSetting("Allow auto-merge", p.Settings.AllowAutoMerge)
+		printBoolSetting("Allow update branch", p.Settings.AllowUpdateBranch)
+		printBoolSetting("Require web commit signoff", p.Settings.WebCommitSignoffRequired)
 		printBoolSetting("Allow squash merge", p.Settings.AllowSquashMerge)
 		printBoolSetting("Allow merge commit", p.Settings.AllowMergeCommit)
 		printBoolSetting("Allow rebase merge", p.Settings.AllowRebaseMerge)
+		printStringSetting("Squash commit title", p.Settings.SquashMergeCommitTitle)
+		printStringSetting("Squash commit message", p.Settings.SquashMergeCommitMessage)
+		printStringSetting("Merge commit title", p.Settings.MergeCommitTitle)
+		printStringSetting("Merge commit message", p.Settings.MergeCommitMessage)
 		fmt.Println()
 
 		fmt.Printf("Labels (%d):\n", len(p.Labels.Items))
@@ -83,9 +130,9 @@ var profilesShowCmd = &cobra.Command{
 		if p.Boilerplate.Gitignore != "" {
 			fmt.Printf("Gitignore: %s\n", p.Boilerplate.Gitignore)
 		}
-		if len(p.Boilerplate.Files) > 0 {
+		if bp := p.EffectiveBoilerplate(); len(bp.Files) > 0 {
 			fmt.Println("Boilerplate files:")
-			for _, f := range p.Boilerplate.Files {
+			for _, f := range bp.Files {
 				fmt.Printf("  %s -> %s\n", f.Src, f.Dest)
 			}
 		}
@@ -95,6 +142,212 @@ var profilesShowCmd = &cobra.Command{
 			fmt.Printf("  Required reviews: %d\n", p.BranchProtection.RequiredReviews)
 		}
 
+		if len(p.Topics.Items) > 0 {
+			mode := "merge"
+			if p.Topics.Replace {
+				mode = "replace"
+			}
+			fmt.Printf("\nTopics (%s): %s\n", mode, strings.Join(p.Topics.Items, ", "))
+		}
+		if p.Homepage != "" {
+			fmt.Printf("Homepage: %s\n", p.Homepage)
+		}
+
+		if len(p.CustomProperties) > 0 {
+			fmt.Println("\nCustom properties:")
+			for _, k := range slices.Sorted(maps.Keys(p.CustomProperties)) {
+				fmt.Printf("  %s: %s\n", k, strings.Join(p.CustomProperties[k], ", "))
+			}
+		}
+
+		if p.Access.IsSet() {
+			fmt.Println("\nAccess:")
+			for _, t := range p.Access.Teams {
+				fmt.Printf("  team %s: %s\n", t.Team, t.Permission)
+			}
+			for _, u := range p.Access.Users {
+				fmt.Printf("  user %s: %s\n", u.User, u.Permission)
+			}
+			if p.Access.Prune {
+				fmt.Println("  (unlisted collaborators and teams are removed)")
+			}
+		}
+
+		a := p.Actions
+		if a.Enabled != nil || a.AllowedActions != "" || a.HasWorkflowPermissions() || len(a.Variables) > 0 || len(a.Secrets) > 0 {
+			fmt.Println("\nActions:")
+			printBoolSetting("Enabled", a.Enabled)
+			printStringSetting("Allowed actions", a.AllowedActions)
+			printBoolSetting("GitHub-owned actions allowed", a.GithubOwnedAllowed)
+			printBoolSetting("Verified creator actions allowed", a.VerifiedAllowed)
+			printStringSetting("Allowed patterns", strings.Join(a.PatternsAllowed, ", "))
+			printStringSetting("Default GITHUB_TOKEN permissions", a.DefaultWorkflowPermissions)
+			printBoolSetting("Workflows can approve PRs", a.CanApprovePullRequestReviews)
+			for _, k := range slices.Sorted(maps.Keys(p.Actions.Variables)) {
+				fmt.Printf("  variable %s: %s\n", k, p.Actions.Variables[k])
+			}
+			for _, k := range slices.Sorted(maps.Keys(p.Actions.Secrets)) {
+				fmt.Printf("  secret %s: from %s\n", k, p.Actions.Secrets[k].Kind())
+			}
+		}
+
+		for _, e := range p.Environments {
+			fmt.Printf("\nEnvironment: %s\n", e.Name)
+			if e.WaitTimer > 0 {
+				fmt.Printf("  Wait timer: %d min\n", e.WaitTimer)
+			}
+			for _, r := range e.Reviewers {
+				if r.Team != "" {
+					fmt.Printf("  Reviewer: team %s\n", r.Team)
+				} else {
+					fmt.Printf("  Reviewer: user %s\n", r.User)
+				}
+			}
+			printStringSetting("Deployment branches", e.DeploymentBranches)
+			printStringSetting("Branch policies", strings.Join(e.BranchPolicies, ", "))
+			for _, k := range slices.Sorted(maps.Keys(e.Variables)) {
+				fmt.Printf("  variable %s: %s\n", k, e.Variables[k])
+			}
+			for _, k := range slices.Sorted(maps.Keys(e.Secrets)) {
+				fmt.Printf("  secret %s: from %s\n", k, e.Secrets[k].Kind())
+			}
+		}
+
+		if p.Pages.Enabled {
+			buildType := "workflow"
+			if !p.Pages.IsWorkflow() {
+				buildType = "legacy"
+			}
+			fmt.Printf("\nPages: %s\n", buildType)
+			printStringSetting("Branch", p.Pages.Branch)
+			printStringSetting("Path", p.Pages.Path)
+			printStringSetting("Custom domain", p.Pages.CNAME)
+			printBoolSetting("Enforce HTTPS", p.Pages.HTTPSEnforced)
+		}
+
+		if len(p.Autolinks) > 0 {
+			fmt.Println("\nAutolinks:")
+			for _, a := range p.Autolinks {
+				fmt.Printf("  %s -> %s\n", a.KeyPrefix, a.URLTemplate)
+			}
+		}
+
+		if len(p.DeployKeys) > 0 {
+			fmt.Println("\nDeploy keys:")
+			for _, k := range p.DeployKeys {
+				source := "from " + k.PublicKeyFile
+				if k.Generate {
+					source = "generated"
+				}
+				access := "read-only"
+				if !k.IsReadOnly() {
+					access = "read-write"
+				}
+				fmt.Printf("  %s (%s, %s)\n", k.Title, source, access)
+			}
+		}
+
+		if len(p.Webhooks) > 0 {
+			fmt.Println("\nWebhooks:")
+			for _, w := range p.Webhooks {
+				if w.Remove {
+					fmt.Printf("  %s (removed)\n", w.URL)
+					continue
+				}
+				events := "push"
+				if len(w.Events) > 0 {
+					events = strings.Join(w.Events, ", ")
+				}
+				fmt.Printf("  %s [%s]\n", w.URL, events)
+			}
+		}
+
+		if cats := p.Discussions.Categories; len(cats) > 0 {
+			fmt.Println("\nDiscussion categories:")
+			for _, c := range cats {
+				format := c.Format
+				if format == "" {
+					format = "open"
+				}
+				fmt.Printf("  %s %s (%s)\n", c.Emoji, c.Name, format)
+			}
+		}
+
+		if proj := p.Project; proj.IsSet() {
+			ref := proj.Title
+			if proj.Number != 0 {
+				ref = fmt.Sprintf("#%d", proj.Number)
+			}
+			if proj.Owner != "" {
+				ref = proj.Owner + " " + ref
+			}
+			fmt.Printf("\nProject: %s\n", ref)
+			if proj.AddSeededIssues {
+				fmt.Println("  Add seeded issues: yes")
+				printStringSetting(proj.Field(), proj.Status)
+			}
+		}
+
+		if len(p.Seed.Milestones) > 0 || len(p.Seed.Issues) > 0 {
+			fmt.Println("\nSeed:")
+			for _, m := range p.Seed.Milestones {
+				if m.DueIn != "" {
+					fmt.Printf("  milestone %s (due in %s)\n", m.Title, m.DueIn)
+				} else {
+					fmt.Printf("  milestone %s\n", m.Title)
+				}
+			}
+			for _, i := range p.Seed.Issues {
+				pinned := ""
+				if i.Pin {
+					pinned = " (pinned)"
+				}
+				fmt.Printf("  issue %q%s\n", i.Title, pinned)
+			}
+		}
+
+		sec := p.Security
+		if sec != (config.SecurityConfig{}) {
+			fmt.Println("\nSecurity:")
+			printBoolSetting("Vulnerability alerts", sec.VulnerabilityAlerts)
+			printBoolSetting("Dependabot security updates", sec.AutomatedSecurityFixes)
+			printBoolSetting("Secret scanning", sec.SecretScanning)
+			printBoolSetting("Push protection", sec.SecretScanningPushProtection)
+			printBoolSetting("Private vulnerability reporting", sec.PrivateVulnerabilityReporting)
+		}
+
+		if l := p.Local; l.IsSet() {
+			fmt.Println("\nLocal clone:")
+			printStringSetting("user.email", l.UserEmail)
+			printStringSetting("Signing key", l.SigningKey)
+			for _, k := range slices.Sorted(maps.Keys(l.GitHooks)) {
+				fmt.Printf("  hook %s: from %s\n", k, l.GitHooks[k])
+			}
+			for _, k := range slices.Sorted(maps.Keys(l.Remotes)) {
+				fmt.Printf("  remote %s: %s\n", k, l.Remotes[k])
+			}
+			for _, argv := range l.PostClone {
+				fmt.Printf("  run: %s\n", strings.Join(argv, " "))
+			}
+		}
+
+		hooks := []struct {
+			name string
+			cmds [][]string
+		}{{"pre_create", p.Hooks.PreCreate}, {"post_create", p.Hooks.PostCreate}, {"post_apply", p.Hooks.PostApply}}
+		for _, h := range hooks {
+			if len(h.cmds) == 0 {
+				continue
+			}
+			fmt.Printf("\nHook %s:\n", h.name)
+			for _, argv := range h.cmds {
+				fmt.Printf("  run: %s\n", strings.Join(argv, " "))
+			}
+		}
+		if len(p.Hooks.PassEnv) > 0 {
+			fmt.Printf("\nHooks see: %s\n", strings.Join(p.Hooks.PassEnv, ", "))
+		}
+
 		return nil
 	},
 }
@@ -109,6 +362,8 @@ func loadConfig() (*config.Config, error) {
 }
 
 func init() {
+	addOutputFlag(profilesListCmd, &profilesListOutput)
+	addOutputFlag(profilesShowCmd, &profilesShowOutput)
 	profilesCmd.AddCommand(profilesListCmd)
 	profilesCmd.AddCommand(profilesShowCmd)
 	rootCmd.AddCommand(profilesCmd)
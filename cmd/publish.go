@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	ghclient "github.com/ggfevans/gh-mint/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var (
+	publishProfile  string
+	publishName     string
+	publishPublic   bool
+	publishPrivate  bool
+	publishTopics   []string
+	publishHomepage string
+	publishPlan     bool
+	publishJobs     int
+	publishOutput   string
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish [path]",
+	Short: "Publish a local project as a new repo with profile defaults",
+	Long:  "Creates a repo for the project at path (default: the current directory), running git init first if needed. Adds the profile's boilerplate files the project doesn't already have, pushes its history, and applies the rest of the profile.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOutputFormat(publishOutput); err != nil {
+			return err
+		}
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		if publishOutput == "" {
+			return runPublish(cmd, dir, nil)
+		}
+		result := &runResult{}
+		return result.finish(publishOutput, runPublish(cmd, dir, result))
+	},
+}
+
+// runPublish publishes the project in dir. With a result, progress is
+// collected into it instead of printed.
+func runPublish(cmd *cobra.Command, dir string, result *runResult) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return invalid(err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return invalid(fmt.Errorf("%s is not a directory", dir))
+	}
+	name := publishName
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	if err := config.ValidateRepoName(name); err != nil {
+		return invalid(fmt.Errorf("%w (set --name)", err))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return invalid(err)
+	}
+
+	profileName := publishProfile
+	if profileName == "" {
+		profileName = cfg.DefaultProfile
+	}
+	profile, ok := cfg.Profiles[profileName]
+	if !ok {
+		return invalid(fmt.Errorf("profile %q not found", profileName))
+	}
+	if err := config.ValidateProfile(profileName, profile); err != nil {
+		return invalid(err)
+	}
+
+	desc := cmd.Flag("description").Value.String()
+	if err := config.ValidateDescription(desc); err != nil {
+		return invalid(err)
+	}
+	if err := config.ValidateTopics(publishTopics); err != nil {
+		return invalid(err)
+	}
+	if err := config.ValidateHomepageTemplate(publishHomepage); err != nil {
+		return invalid(err)
+	}
+
+	public := publishPublic
+	if publishPrivate {
+		public = false
+	}
+
+	opts := ghclient.CreateOpts{
+		Name:         name,
+		Description:  desc,
+		Public:       public,
+		Profile:      profile,
+		Owner:        cfg.DefaultOwner,
+		Topics:       publishTopics,
+		Homepage:     publishHomepage,
+		OnProgress:   printStep,
+		OnPrivateKey: printPrivateKey,
+		Concurrency:  publishJobs,
+	}
+	if result != nil {
+		if result.Profile, err = resolvedProfile(profileName, profile); err != nil {
+			return err
+		}
+		result.collect(&opts)
+		opts.OnPrivateKey = func(title string, key []byte) {
+			fmt.Fprintf(os.Stderr, "Private key for deploy key %q (shown once, save it now):\n\n%s\n", title, key)
+		}
+	}
+
+	if publishPlan {
+		var plan []ghclient.PlanStep
+		if len(profile.Hooks.PreCreate) > 0 {
+			plan = append(plan, ghclient.PlanStep{ID: "pre-create", Name: "Ran pre_create hooks"})
+		}
+		plan = append(plan, ghclient.PlanStep{ID: "create", Name: "Created repository"})
+		plan = append(plan, ghclient.PublishPlan(opts)...)
+		if result != nil {
+			result.Plan = plan
+			return nil
+		}
+		printPlan(plan)
+		return nil
+	}
+
+	client := ghclient.NewClient()
+	if err := client.CheckInstalled(); err != nil {
+		return err
+	}
+
+	if result == nil {
+		fmt.Printf("Publishing %s as %s with profile %q...\n", dir, name, profileName)
+	}
+	url, err := client.PublishWithDefaults(dir, opts)
+	var exists *ghclient.RepoExistsError
+	if errors.As(err, &exists) {
+		if result != nil {
+			result.NWO = exists.NWO
+		}
+		return fmt.Errorf("%w; choose another name with --name", err)
+	}
+	if result != nil {
+		result.URL = url
+		result.NWO = nwoFromURL(url)
+	}
+	if err != nil {
+		if url != "" {
+			return partial(err)
+		}
+		return err
+	}
+	if result == nil {
+		fmt.Printf("\nDone! %s\n", url)
+	}
+	return nil
+}
+
+func init() {
+	publishCmd.Flags().StringVarP(&publishProfile, "profile", "p", "", "Profile to apply (default: from config)")
+	publishCmd.Flags().StringVar(&publishName, "name", "", "Repo name (default: the directory name)")
+	publishCmd.Flags().BoolVar(&publishPublic, "public", false, "Create public repo")
+	publishCmd.Flags().BoolVar(&publishPrivate, "private", false, "Create private repo")
+	publishCmd.MarkFlagsMutuallyExclusive("public", "private")
+	publishCmd.Flags().String("description", "", "Repo description")
+	publishCmd.Flags().StringArrayVar(&publishTopics, "topic", nil, "Topic to add (repeatable, merged with profile topics)")
+	publishCmd.Flags().StringVar(&publishHomepage, "homepage", "", "Homepage URL (overrides profile homepage)")
+	publishCmd.Flags().BoolVar(&publishPlan, "plan", false, "Print the steps and their dependencies without running them")
+	publishCmd.Flags().IntVar(&publishJobs, "concurrency", 0, "Steps to run at once (default 4)")
+	addOutputFlag(publishCmd, &publishOutput)
+	rootCmd.AddCommand(publishCmd)
+}
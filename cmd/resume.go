@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	ghclient "github.com/ggfevans/gh-mint/internal/github"
+	"github.com/ggfevans/gh-mint/internal/journal"
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume [run-id]",
+	Short: "Retry the failed steps of an earlier create",
+	Long:  "Retries the steps of a create run that failed or never ran, without creating the repo again. With no run ID, lists runs that can be resumed.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := journal.Dir()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return listResumableRuns(dir)
+		}
+
+		run, err := journal.Load(dir, args[0])
+		if err != nil {
+			return err
+		}
+		if run.Succeeded("rollback") {
+			return fmt.Errorf("run %s was rolled back and its repository deleted", run.ID)
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		profile, ok := cfg.Profiles[run.Profile]
+		if !ok {
+			return fmt.Errorf("profile %q from run %s no longer exists", run.Profile, run.ID)
+		}
+		if err := config.ValidateProfile(run.Profile, profile); err != nil {
+			return invalid(err)
+		}
+
+		client := ghclient.NewClient()
+		if err := client.CheckInstalled(); err != nil {
+			return err
+		}
+
+		target := run.NWO
+		if target == "" {
+			target = run.Name
+		}
+		fmt.Printf("Resuming run %s for %s with profile %q...\n", run.ID, target, run.Profile)
+		for _, s := range run.Unfinished() {
+			switch {
+			case s.ID == "rollback":
+			case s.Error != "":
+				fmt.Printf("  retrying: %s (%s)\n", s.Name, s.Error)
+			default:
+				fmt.Printf("  running: %s (never finished)\n", s.Name)
+			}
+		}
+
+		opts := ghclient.CreateOpts{
+			Name:         run.Name,
+			Description:  run.Description,
+			Public:       run.Public,
+			Profile:      profile,
+			Owner:        run.Owner,
+			Topics:       run.Topics,
+			Homepage:     run.Homepage,
+			Template:     run.Template,
+			CloneDir:     run.CloneDir,
+			OnProgress:   printStep,
+			OnPrivateKey: printPrivateKey,
+			Journal:      run,
+		}
+		url, err := client.CreateWithDefaults(opts)
+		if err != nil {
+			if run.Succeeded("create") {
+				fmt.Printf("\nResume again with: gh mint resume %s\n", run.ID)
+				return partial(err)
+			}
+			return err
+		}
+		fmt.Printf("\nDone! %s\n", url)
+		return nil
+	},
+}
+
+func listResumableRuns(dir string) error {
+	runs, err := journal.List(dir)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, r := range runs {
+		if !r.Resumable() {
+			continue
+		}
+		if !found {
+			fmt.Println("Runs to resume:")
+			found = true
+		}
+		target := r.NWO
+		if target == "" {
+			target = r.Name
+		}
+		state := fmt.Sprintf("%d failed", len(r.Failed()))
+		if r.Finished == nil {
+			state = "interrupted"
+		}
+		fmt.Printf("  %s  %s  %s, %d steps left\n", r.ID, target, state, len(r.Unfinished()))
+	}
+	if !found {
+		fmt.Println("No runs to resume.")
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+}
@@ -31,5 +31,8 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() error {
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return invalid(err)
+	})
 	return rootCmd.Execute()
 }
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -18,23 +21,109 @@ type Config struct {
 }
 
 type Profile struct {
-	Description      string            `yaml:"description"`
-	Settings         RepoSettings      `yaml:"settings"`
-	Labels           LabelConfig       `yaml:"labels"`
-	Boilerplate      BoilerplateConfig `yaml:"boilerplate"`
-	BranchProtection BranchProtection  `yaml:"branch_protection"`
+	Description      string                   `yaml:"description"`
+	TemplateRepo     string                   `yaml:"template_repo"` // owner/name to generate new repos from
+	Settings         RepoSettings             `yaml:"settings"`
+	Labels           LabelConfig              `yaml:"labels"`
+	Boilerplate      BoilerplateConfig        `yaml:"boilerplate"`
+	BranchProtection BranchProtection         `yaml:"branch_protection"`
+	Security         SecurityConfig           `yaml:"security"`
+	Topics           TopicConfig              `yaml:"topics"`
+	Homepage         string                   `yaml:"homepage"`
+	CustomProperties map[string]PropertyValue `yaml:"custom_properties"`
+	Access           AccessConfig             `yaml:"access"`
+	Actions          ActionsConfig            `yaml:"actions"`
+	Environments     []Environment            `yaml:"environments"`
+	Webhooks         []Webhook                `yaml:"webhooks"`
+	Autolinks        []Autolink               `yaml:"autolinks"`
+	Pages            PagesConfig              `yaml:"pages"`
+	Seed             SeedConfig               `yaml:"seed"`
+	Discussions      DiscussionsConfig        `yaml:"discussions"`
+	Project          ProjectConfig            `yaml:"project"`
+	DeployKeys       []DeployKey              `yaml:"deploy_keys"`
+	Fork             ForkConfig               `yaml:"fork"`
+	Local            LocalConfig              `yaml:"local"`
+	Hooks            HooksConfig              `yaml:"hooks"`
+}
+
+// pagesWorkflowFile is the boilerplate added when pages.workflow is set.
+var pagesWorkflowFile = BoilerplateFile{Src: "pages.yml", Dest: ".github/workflows/pages.yml"}
+
+// EffectiveBoilerplate returns the profile's boilerplate plus any files
+// other sections contribute, such as the starter Pages workflow.
+func (p Profile) EffectiveBoilerplate() BoilerplateConfig {
+	bp := p.Boilerplate
+	if p.Pages.Workflow {
+		bp.Files = append(append([]BoilerplateFile(nil), bp.Files...), pagesWorkflowFile)
+	}
+	return bp
+}
+
+// upstreamSyncFile is the workflow added to forks when fork.sync_upstream
+// is set.
+var upstreamSyncFile = BoilerplateFile{Src: "upstream-sync.yml", Dest: ".github/workflows/upstream-sync.yml"}
+
+// ForkBoilerplate returns the files pushed to a fork. The profile's own
+// boilerplate is left out so upstream files are never overwritten.
+func (p Profile) ForkBoilerplate() BoilerplateConfig {
+	var bp BoilerplateConfig
+	if p.Fork.SyncUpstream {
+		bp.Files = []BoilerplateFile{upstreamSyncFile}
+	}
+	return bp
+}
+
+// ForkConfig applies to repos made with `gh mint fork`.
+type ForkConfig struct {
+	SyncUpstream bool `yaml:"sync_upstream"` // add a scheduled workflow that syncs the default branch from upstream
+}
+
+// LocalConfig applies to the local clone made with `gh mint create --clone`.
+type LocalConfig struct {
+	UserEmail  string            `yaml:"user_email"`  // git user.email for the clone
+	SigningKey string            `yaml:"signing_key"` // git user.signingkey; also turns on commit signing
+	GitHooks   map[string]string `yaml:"git_hooks"`   // hook name to template, e.g. pre-commit: pre-commit.sh
+	Remotes    map[string]string `yaml:"remotes"`     // remote name to URL template, e.g. "git@gitlab.com:{{.NWO}}.git"
+	PostClone  [][]string        `yaml:"post_clone"`  // commands run in the clone, as argument lists
+}
+
+func (l LocalConfig) IsSet() bool {
+	return l.UserEmail != "" || l.SigningKey != "" || len(l.GitHooks) > 0 || len(l.Remotes) > 0 || len(l.PostClone) > 0
+}
+
+// SSHSigning reports whether the signing key is an SSH key, given inline
+// or as a path to a public key, rather than a GPG key ID.
+func (l LocalConfig) SSHSigning() bool {
+	return strings.HasPrefix(l.SigningKey, "ssh-") || strings.HasSuffix(l.SigningKey, ".pub")
+}
+
+// HooksConfig lists local commands to run around a create, publish, or
+// apply. Each command is an argument list run without a shell.
+type HooksConfig struct {
+	PreCreate  [][]string `yaml:"pre_create"`  // before the repo is created; a failure stops the create
+	PostCreate [][]string `yaml:"post_create"` // in the working tree before the boilerplate commit
+	PostApply  [][]string `yaml:"post_apply"`  // after every apply step succeeded
+	PassEnv    []string   `yaml:"pass_env"`    // variables such as GH_TOKEN passed through to every hook
 }
 
 type RepoSettings struct {
+	HasIssues                *bool  `yaml:"has_issues" json:"has_issues,omitempty"`
 	HasWiki                  *bool  `yaml:"has_wiki" json:"has_wiki,omitempty"`
 	HasProjects              *bool  `yaml:"has_projects" json:"has_projects,omitempty"`
 	HasDiscussions           *bool  `yaml:"has_discussions" json:"has_discussions,omitempty"`
+	IsTemplate               *bool  `yaml:"is_template" json:"is_template,omitempty"`
+	AllowForking             *bool  `yaml:"allow_forking" json:"allow_forking,omitempty"`
 	DeleteBranchOnMerge      *bool  `yaml:"delete_branch_on_merge" json:"delete_branch_on_merge,omitempty"`
+	AllowAutoMerge           *bool  `yaml:"allow_auto_merge" json:"allow_auto_merge,omitempty"`
+	AllowUpdateBranch        *bool  `yaml:"allow_update_branch" json:"allow_update_branch,omitempty"`
+	WebCommitSignoffRequired *bool  `yaml:"web_commit_signoff_required" json:"web_commit_signoff_required,omitempty"`
 	AllowSquashMerge         *bool  `yaml:"allow_squash_merge" json:"allow_squash_merge,omitempty"`
 	AllowMergeCommit         *bool  `yaml:"allow_merge_commit" json:"allow_merge_commit,omitempty"`
 	AllowRebaseMerge         *bool  `yaml:"allow_rebase_merge" json:"allow_rebase_merge,omitempty"`
 	SquashMergeCommitTitle   string `yaml:"squash_merge_commit_title" json:"squash_merge_commit_title,omitempty"`
 	SquashMergeCommitMessage string `yaml:"squash_merge_commit_message" json:"squash_merge_commit_message,omitempty"`
+	MergeCommitTitle         string `yaml:"merge_commit_title" json:"merge_commit_title,omitempty"`
+	MergeCommitMessage       string `yaml:"merge_commit_message" json:"merge_commit_message,omitempty"`
 }
 
 // boolPtr is a helper for creating *bool values.
@@ -51,6 +140,284 @@ type Label struct {
 	Description string `yaml:"description"`
 }
 
+// TopicConfig lists repo topics. With Replace unset, they are merged into
+// the repo's existing topics.
+type TopicConfig struct {
+	Replace bool     `yaml:"replace"`
+	Items   []string `yaml:"items"`
+}
+
+// PropertyValue is an organisation custom property value. YAML accepts a
+// scalar or, for multi_select properties, a list.
+type PropertyValue []string
+
+func (v *PropertyValue) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*v = PropertyValue{node.Value}
+		return nil
+	case yaml.SequenceNode:
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		*v = list
+		return nil
+	}
+	return fmt.Errorf("line %d: custom property value must be a string or a list of strings", node.Line)
+}
+
+// AccessConfig grants teams and users a permission on the repo. With Prune
+// set, direct collaborators and teams not listed are removed.
+type AccessConfig struct {
+	Prune bool         `yaml:"prune"`
+	Teams []TeamAccess `yaml:"teams"`
+	Users []UserAccess `yaml:"users"`
+}
+
+// TeamAccess names a team as "slug", "org/slug", or "@org/slug". A bare
+// slug refers to a team in the repo owner's organisation.
+type TeamAccess struct {
+	Team       string `yaml:"team"`
+	Permission string `yaml:"permission"`
+}
+
+// OrgSlug splits the team reference, defaulting the org to owner.
+func (t TeamAccess) OrgSlug(owner string) (string, string) {
+	ref := strings.TrimPrefix(t.Team, "@")
+	if org, slug, ok := strings.Cut(ref, "/"); ok {
+		return org, slug
+	}
+	return owner, ref
+}
+
+// IsSet reports whether the profile configures any access changes.
+func (a AccessConfig) IsSet() bool {
+	return a.Prune || len(a.Teams) > 0 || len(a.Users) > 0
+}
+
+type UserAccess struct {
+	User       string `yaml:"user"`
+	Permission string `yaml:"permission"`
+}
+
+// ActionsConfig sets the repo's GitHub Actions policy and provisions
+// Actions variables and secrets.
+type ActionsConfig struct {
+	Enabled *bool `yaml:"enabled"`
+	// AllowedActions is "all", "local_only", or "selected". With "selected",
+	// GithubOwnedAllowed, VerifiedAllowed, and PatternsAllowed form the allowlist.
+	AllowedActions     string   `yaml:"allowed_actions"`
+	GithubOwnedAllowed *bool    `yaml:"github_owned_allowed"`
+	VerifiedAllowed    *bool    `yaml:"verified_allowed"`
+	PatternsAllowed    []string `yaml:"patterns_allowed"`
+	// DefaultWorkflowPermissions is the GITHUB_TOKEN default, "read" or "write".
+	DefaultWorkflowPermissions   string `yaml:"default_workflow_permissions"`
+	CanApprovePullRequestReviews *bool  `yaml:"can_approve_pull_request_reviews"`
+
+	Variables map[string]string       `yaml:"variables"`
+	Secrets   map[string]SecretSource `yaml:"secrets"`
+}
+
+// HasSelectedActions reports whether an allowlist is configured.
+func (a ActionsConfig) HasSelectedActions() bool {
+	return a.GithubOwnedAllowed != nil || a.VerifiedAllowed != nil || len(a.PatternsAllowed) > 0
+}
+
+// HasWorkflowPermissions reports whether GITHUB_TOKEN defaults are configured.
+func (a ActionsConfig) HasWorkflowPermissions() bool {
+	return a.DefaultWorkflowPermissions != "" || a.CanApprovePullRequestReviews != nil
+}
+
+// SecretSource says where a secret value comes from. Exactly one field is
+// set; values are never stored in the config itself.
+type SecretSource struct {
+	Env     string   `yaml:"env"`
+	File    string   `yaml:"file"`
+	Command []string `yaml:"command"`
+}
+
+// Kind describes the source for display, without revealing the value.
+func (s SecretSource) Kind() string {
+	switch {
+	case s.Env != "":
+		return "env " + s.Env
+	case s.File != "":
+		return "file " + s.File
+	case len(s.Command) > 0:
+		return "command " + s.Command[0]
+	}
+	return "unset"
+}
+
+// Environment is a deployment environment and its protection rules.
+type Environment struct {
+	Name      string     `yaml:"name"`
+	WaitTimer int        `yaml:"wait_timer"` // minutes
+	Reviewers []Reviewer `yaml:"reviewers"`
+	// DeploymentBranches is "all" (the default), "protected", or "custom".
+	// With "custom", BranchPolicies lists the branch name patterns allowed.
+	DeploymentBranches string                  `yaml:"deployment_branches"`
+	BranchPolicies     []string                `yaml:"branch_policies"`
+	Variables          map[string]string       `yaml:"variables"`
+	Secrets            map[string]SecretSource `yaml:"secrets"`
+}
+
+// Reviewer is a required deployment reviewer: a team (same forms as
+// TeamAccess) or a user.
+type Reviewer struct {
+	Team string `yaml:"team"`
+	User string `yaml:"user"`
+}
+
+// Webhook is a repo webhook, reconciled by URL. Active defaults to true and
+// Events to ["push"]. With Remove set, hooks with this URL are deleted.
+type Webhook struct {
+	URL         string   `yaml:"url"`
+	ContentType string   `yaml:"content_type"` // json (default) or form
+	Events      []string `yaml:"events"`
+	Active      *bool    `yaml:"active"`
+	SecretEnv   string   `yaml:"secret_env"` // env var holding the signing secret
+	Remove      bool     `yaml:"remove"`
+}
+
+// Autolink turns references like JIRA-123 into links. URLTemplate must
+// contain <num>. Alphanumeric defaults to true, as on GitHub.
+type Autolink struct {
+	KeyPrefix    string `yaml:"key_prefix"`
+	URLTemplate  string `yaml:"url_template"`
+	Alphanumeric *bool  `yaml:"alphanumeric"`
+}
+
+// IsAlphanumeric reports whether the reference may contain letters.
+func (a Autolink) IsAlphanumeric() bool {
+	return a.Alphanumeric == nil || *a.Alphanumeric
+}
+
+// DeployKey is an SSH key with access to a single repo. The public key is
+// read from PublicKeyFile, or a new ed25519 keypair is generated when
+// Generate is set; its private half is written to PrivateKeyPath, or
+// printed once when no path is given. Title and PrivateKeyPath are
+// templates with the same fields as homepage. ReadOnly defaults to true.
+type DeployKey struct {
+	Title          string `yaml:"title"`
+	PublicKeyFile  string `yaml:"public_key_file"`
+	Generate       bool   `yaml:"generate"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+	ReadOnly       *bool  `yaml:"read_only"`
+}
+
+// IsReadOnly reports whether the key is denied write access.
+func (k DeployKey) IsReadOnly() bool {
+	return k.ReadOnly == nil || *k.ReadOnly
+}
+
+// PagesConfig enables GitHub Pages. BuildType is "workflow" (GitHub
+// Actions, the default) or "legacy" (publish from Branch and Path). CNAME is
+// a template like "{{.Name}}.docs.example.com".
+type PagesConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	BuildType     string `yaml:"build_type"`
+	Branch        string `yaml:"branch"`
+	Path          string `yaml:"path"` // "/" or "/docs"
+	CNAME         string `yaml:"cname"`
+	HTTPSEnforced *bool  `yaml:"https_enforced"`
+	Workflow      bool   `yaml:"workflow"` // scaffold a starter Pages workflow
+}
+
+// IsWorkflow reports whether Pages is built by a GitHub Actions workflow.
+func (p PagesConfig) IsWorkflow() bool {
+	return p.BuildType == "" || p.BuildType == "workflow"
+}
+
+// DiscussionsConfig lists the discussion categories a repo should have.
+type DiscussionsConfig struct {
+	Categories []DiscussionCategory `yaml:"categories"`
+}
+
+// DiscussionCategory's Format is open (default), qa, or announcement.
+type DiscussionCategory struct {
+	Name        string `yaml:"name"`
+	Emoji       string `yaml:"emoji"`
+	Description string `yaml:"description"`
+	Format      string `yaml:"format"`
+}
+
+// IsAnswerable reports whether the category accepts answers (Q&A format).
+func (d DiscussionCategory) IsAnswerable() bool {
+	return d.Format == "qa"
+}
+
+// ProjectConfig names a Projects (v2) board to link repos to, by number or
+// title. Owner defaults to the repo owner. When AddSeededIssues is set,
+// seed issues are added to the project with Status as the value of the
+// single-select StatusField (default "Status").
+type ProjectConfig struct {
+	Owner           string `yaml:"owner"`
+	Number          int    `yaml:"number"`
+	Title           string `yaml:"title"`
+	AddSeededIssues bool   `yaml:"add_seeded_issues"`
+	Status          string `yaml:"status"`
+	StatusField     string `yaml:"status_field"`
+}
+
+// IsSet reports whether the profile names a project.
+func (p ProjectConfig) IsSet() bool {
+	return p.Number != 0 || p.Title != ""
+}
+
+// Field returns the single-select field that Status is written to.
+func (p ProjectConfig) Field() string {
+	if p.StatusField == "" {
+		return "Status"
+	}
+	return p.StatusField
+}
+
+// SeedConfig lists milestones and issues opened on a newly created repo.
+type SeedConfig struct {
+	Milestones []SeedMilestone `yaml:"milestones"`
+	Issues     []SeedIssue     `yaml:"issues"`
+}
+
+// SeedMilestone's DueIn is relative to creation, e.g. "14d" or "6w".
+type SeedMilestone struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	DueIn       string `yaml:"due_in"`
+}
+
+// SeedIssue's Body is a template with the same fields as homepage. Labels
+// must come from the profile's labels and Milestone from seed milestones.
+type SeedIssue struct {
+	Title     string   `yaml:"title"`
+	Body      string   `yaml:"body"`
+	Labels    []string `yaml:"labels"`
+	Assignees []string `yaml:"assignees"`
+	Milestone string   `yaml:"milestone"`
+	Pin       bool     `yaml:"pin"`
+}
+
+// ParseDueIn parses a relative due date of whole days ("14d") or weeks
+// ("6w") into a duration.
+func ParseDueIn(s string) (time.Duration, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("due_in %q must be a number of days or weeks, e.g. 14d or 6w", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("due_in %q must be a number of days or weeks, e.g. 14d or 6w", s)
+	}
+	day := 24 * time.Hour
+	switch s[len(s)-1] {
+	case 'd':
+		return time.Duration(n) * day, nil
+	case 'w':
+		return time.Duration(n) * 7 * day, nil
+	}
+	return 0, fmt.Errorf("due_in %q must be a number of days or weeks, e.g. 14d or 6w", s)
+}
+
 type BoilerplateConfig struct {
 	License   string            `yaml:"license"`
 	Gitignore string            `yaml:"gitignore"`
@@ -69,6 +436,16 @@ type BranchProtection struct {
 	RequireStatusChecks bool   `yaml:"require_status_checks"`
 }
 
+// SecurityConfig toggles security and analysis features. Unset fields are
+// left as GitHub has them.
+type SecurityConfig struct {
+	VulnerabilityAlerts           *bool `yaml:"vulnerability_alerts"`
+	AutomatedSecurityFixes        *bool `yaml:"automated_security_fixes"`
+	SecretScanning                *bool `yaml:"secret_scanning"`
+	SecretScanningPushProtection  *bool `yaml:"secret_scanning_push_protection"`
+	PrivateVulnerabilityReporting *bool `yaml:"private_vulnerability_reporting"`
+}
+
 func LoadFromFile(path string) (*Config, error) {
 	info, err := os.Stat(path)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_FromYAML(t *testing.T) {
@@ -112,3 +113,101 @@ profiles: {}
 		t.Fatalf("LoadFromFile: %v", err)
 	}
 }
+
+func TestLoadConfig_CustomProperties(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	yaml := `profiles:
+  service:
+    custom_properties:
+      team: platform
+      tier: 1
+      data-classification: [pii, internal]
+`
+	if err := os.WriteFile(configPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	props := cfg.Profiles["service"].CustomProperties
+	if len(props["team"]) != 1 || props["team"][0] != "platform" {
+		t.Errorf("team = %v, want [platform]", props["team"])
+	}
+	if len(props["tier"]) != 1 || props["tier"][0] != "1" {
+		t.Errorf("tier = %v, want [1]", props["tier"])
+	}
+	if len(props["data-classification"]) != 2 {
+		t.Errorf("data-classification = %v, want 2 values", props["data-classification"])
+	}
+}
+
+func TestTeamAccess_OrgSlug(t *testing.T) {
+	tests := []struct {
+		team     string
+		wantOrg  string
+		wantSlug string
+	}{
+		{"platform", "acme", "platform"},
+		{"acme/platform", "acme", "platform"},
+		{"@other/security", "other", "security"},
+	}
+	for _, tt := range tests {
+		org, slug := TeamAccess{Team: tt.team}.OrgSlug("acme")
+		if org != tt.wantOrg || slug != tt.wantSlug {
+			t.Errorf("OrgSlug(%q) = %q, %q, want %q, %q", tt.team, org, slug, tt.wantOrg, tt.wantSlug)
+		}
+	}
+}
+
+func TestEffectiveBoilerplate_PagesWorkflow(t *testing.T) {
+	p := Profile{
+		Boilerplate: BoilerplateConfig{Files: []BoilerplateFile{{Src: "ci.yml", Dest: ".github/workflows/ci.yml"}}},
+		Pages:       PagesConfig{Enabled: true, Workflow: true},
+	}
+	bp := p.EffectiveBoilerplate()
+	if len(bp.Files) != 2 || bp.Files[1].Src != "pages.yml" {
+		t.Errorf("files = %+v, want ci.yml and pages.yml", bp.Files)
+	}
+	if len(p.Boilerplate.Files) != 1 {
+		t.Error("EffectiveBoilerplate should not modify the profile")
+	}
+}
+
+func TestForkBoilerplate(t *testing.T) {
+	p := Profile{Boilerplate: BoilerplateConfig{License: "mit", Files: []BoilerplateFile{{Src: "ci.yml", Dest: ".github/workflows/ci.yml"}}}}
+	if bp := p.ForkBoilerplate(); len(bp.Files) != 0 || bp.License != "" {
+		t.Errorf("fork boilerplate = %+v, want nothing without sync_upstream", bp)
+	}
+	p.Fork.SyncUpstream = true
+	if bp := p.ForkBoilerplate(); len(bp.Files) != 1 || bp.Files[0].Src != "upstream-sync.yml" {
+		t.Errorf("fork boilerplate = %+v, want only upstream-sync.yml", bp)
+	}
+}
+
+func TestParseDueIn(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"14d", 14 * 24 * time.Hour, false},
+		{"6w", 42 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"d", 0, true},
+		{"2m", 0, true},
+		{"-1w", 0, true},
+		{"1.5w", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDueIn(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDueIn(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDueIn(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
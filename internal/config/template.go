@@ -0,0 +1,38 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// RepoVars are the fields available to profile string templates such as
+// homepage, e.g. "https://{{.Owner}}.github.io/{{.Name}}".
+type RepoVars struct {
+	Owner string
+	Name  string
+	NWO   string
+}
+
+// NewRepoVars splits an owner/repo string into template variables.
+func NewRepoVars(nwo string) RepoVars {
+	owner, name, ok := strings.Cut(nwo, "/")
+	if !ok {
+		return RepoVars{Name: nwo, NWO: nwo}
+	}
+	return RepoVars{Owner: owner, Name: name, NWO: nwo}
+}
+
+// RenderTemplate executes a profile string template against vars.
+func RenderTemplate(text string, vars RepoVars) (string, error) {
+	tmpl, err := template.New("profile").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing template %q: %w", text, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("rendering template %q: %w", text, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestNewRepoVars(t *testing.T) {
+	v := NewRepoVars("acme/widget")
+	if v.Owner != "acme" || v.Name != "widget" || v.NWO != "acme/widget" {
+		t.Errorf("NewRepoVars = %+v", v)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	got, err := RenderTemplate("https://{{.Owner}}.github.io/{{.Name}}", NewRepoVars("acme/widget"))
+	if err != nil {
+		t.Fatalf("RenderTemplate: %v", err)
+	}
+	if got != "https://acme.github.io/widget" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRenderTemplate_UnknownField(t *testing.T) {
+	if _, err := RenderTemplate("{{.Team}}", NewRepoVars("acme/widget")); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -9,13 +10,45 @@ import (
 )
 
 var (
-	repoNamePattern    = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
-	labelColorPattern  = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
-	profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-	nwoPattern         = regexp.MustCompile(`^[a-zA-Z0-9._-]+/[a-zA-Z0-9._-]+$`)
-	branchNamePattern  = regexp.MustCompile(`^[a-zA-Z0-9._/-]+$`)
+	repoNamePattern     = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+	labelColorPattern   = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+	profileNamePattern  = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	nwoPattern          = regexp.MustCompile(`^[a-zA-Z0-9._-]+/[a-zA-Z0-9._-]+$`)
+	branchNamePattern   = regexp.MustCompile(`^[a-zA-Z0-9._/-]+$`)
+	topicPattern        = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	propertyNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_$#-]+$`)
+	userLoginPattern    = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,38})$`)
+	actionsNamePattern  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	webhookEventPattern = regexp.MustCompile(`^(\*|[a-z_]+)$`)
+	hostnamePattern     = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+	autolinkKeyPattern  = regexp.MustCompile(`^[a-zA-Z0-9._+=:/#-]+$`)
+	envVarPattern       = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	environmentPattern  = regexp.MustCompile(`^[a-zA-Z0-9._ -]+$`)
+	teamRefPattern      = regexp.MustCompile(`^@?(?:[a-zA-Z0-9][a-zA-Z0-9-]*/)?[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+	emojiPattern        = regexp.MustCompile(`^:[a-z0-9_+-]+:$`)
+	remoteNamePattern   = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
 )
 
+var (
+	accessPermissions   = []string{"pull", "triage", "push", "maintain", "admin"}
+	allowedActions      = []string{"all", "local_only", "selected"}
+	workflowPermissions = []string{"read", "write"}
+	deploymentBranches  = []string{"all", "protected", "custom"}
+	webhookContentTypes = []string{"json", "form"}
+	pagesBuildTypes     = []string{"workflow", "legacy"}
+	pagesPaths          = []string{"/", "/docs"}
+	discussionFormats   = []string{"open", "qa", "announcement"}
+	// Client-side hooks; see githooks(5).
+	gitHookNames = []string{
+		"applypatch-msg", "pre-applypatch", "post-applypatch", "pre-commit",
+		"pre-merge-commit", "prepare-commit-msg", "commit-msg", "post-commit",
+		"pre-rebase", "post-checkout", "post-merge", "pre-push", "post-rewrite",
+		"pre-auto-gc",
+	}
+)
+
+const maxTopics = 20
+
 func ValidateRepoName(name string) error {
 	if name == "" {
 		return fmt.Errorf("repo name cannot be empty")
@@ -84,6 +117,527 @@ func ValidateBranchName(name string) error {
 	return nil
 }
 
+func ValidateTopic(topic string) error {
+	if topic == "" {
+		return fmt.Errorf("topic cannot be empty")
+	}
+	if len(topic) > 50 {
+		return fmt.Errorf("topic %q cannot exceed 50 characters", topic)
+	}
+	if !topicPattern.MatchString(topic) {
+		return fmt.Errorf("topic %q must be lowercase letters, numbers, and hyphens, starting with a letter or number", topic)
+	}
+	return nil
+}
+
+func ValidateTopics(topics []string) error {
+	if len(topics) > maxTopics {
+		return fmt.Errorf("cannot set more than %d topics", maxTopics)
+	}
+	for _, t := range topics {
+		if err := ValidateTopic(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateHomepage checks that a homepage, after rendering, is an http(s) URL.
+func ValidateHomepage(homepage string) error {
+	if homepage == "" {
+		return nil
+	}
+	u, err := url.Parse(homepage)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("homepage %q must be an http(s) URL", homepage)
+	}
+	return nil
+}
+
+// ValidateHomepageTemplate renders a homepage template with sample values
+// and validates the result.
+func ValidateHomepageTemplate(tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+	rendered, err := RenderTemplate(tmpl, NewRepoVars("owner/repo"))
+	if err != nil {
+		return err
+	}
+	return ValidateHomepage(rendered)
+}
+
+func ValidateCustomProperties(props map[string]PropertyValue) error {
+	for name, value := range props {
+		if len(name) > 75 || !propertyNamePattern.MatchString(name) {
+			return fmt.Errorf("custom property name %q is invalid (allowed: a-z, 0-9, '_', '-', '$', '#', max 75 characters)", name)
+		}
+		if len(value) == 0 {
+			return fmt.Errorf("custom property %q has no value", name)
+		}
+		for _, v := range value {
+			if v == "" {
+				return fmt.Errorf("custom property %q has an empty value", name)
+			}
+			if len(v) > 75 {
+				return fmt.Errorf("custom property %q value cannot exceed 75 characters", name)
+			}
+		}
+	}
+	return nil
+}
+
+func ValidateUserLogin(login string) error {
+	if !userLoginPattern.MatchString(login) {
+		return fmt.Errorf("user %q is not a valid GitHub login", login)
+	}
+	return nil
+}
+
+func ValidateAccess(a AccessConfig) error {
+	teams := make(map[string]bool)
+	for _, t := range a.Teams {
+		if !teamRefPattern.MatchString(t.Team) {
+			return fmt.Errorf("team %q must be \"slug\", \"org/slug\", or \"@org/slug\"", t.Team)
+		}
+		if t.Permission == "" {
+			return fmt.Errorf("team %q has no permission", t.Team)
+		}
+		if err := validateEnum("team "+t.Team+" permission", t.Permission, accessPermissions); err != nil {
+			return err
+		}
+		key := strings.ToLower(strings.TrimPrefix(t.Team, "@"))
+		if teams[key] {
+			return fmt.Errorf("team %q is listed more than once", t.Team)
+		}
+		teams[key] = true
+	}
+	users := make(map[string]bool)
+	for _, u := range a.Users {
+		if err := ValidateUserLogin(u.User); err != nil {
+			return err
+		}
+		if u.Permission == "" {
+			return fmt.Errorf("user %q has no permission", u.User)
+		}
+		if err := validateEnum("user "+u.User+" permission", u.Permission, accessPermissions); err != nil {
+			return err
+		}
+		key := strings.ToLower(u.User)
+		if users[key] {
+			return fmt.Errorf("user %q is listed more than once", u.User)
+		}
+		users[key] = true
+	}
+	return nil
+}
+
+// ValidateActionsPolicy checks enum values and that the allowlist and token
+// settings aren't set for a repo with Actions disabled.
+func ValidateActionsPolicy(a ActionsConfig) error {
+	if err := validateEnum("allowed_actions", a.AllowedActions, allowedActions); err != nil {
+		return err
+	}
+	if err := validateEnum("default_workflow_permissions", a.DefaultWorkflowPermissions, workflowPermissions); err != nil {
+		return err
+	}
+	if isFalse(a.Enabled) && (a.AllowedActions != "" || a.HasSelectedActions() || a.HasWorkflowPermissions()) {
+		return fmt.Errorf("actions policy settings require enabled to be true")
+	}
+	if a.HasSelectedActions() && a.AllowedActions != "selected" {
+		return fmt.Errorf("github_owned_allowed, verified_allowed, and patterns_allowed require allowed_actions: selected")
+	}
+	for _, p := range a.PatternsAllowed {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("patterns_allowed cannot contain empty patterns")
+		}
+	}
+	return nil
+}
+
+// ValidateActionsName checks a secret or variable name.
+func ValidateActionsName(name string) error {
+	if !actionsNamePattern.MatchString(name) {
+		return fmt.Errorf("name %q must contain only letters, numbers, and underscores, and not start with a number", name)
+	}
+	if strings.HasPrefix(strings.ToUpper(name), "GITHUB_") {
+		return fmt.Errorf("name %q cannot start with GITHUB_", name)
+	}
+	return nil
+}
+
+func ValidateSecretSource(s SecretSource) error {
+	set := 0
+	if s.Env != "" {
+		set++
+	}
+	if s.File != "" {
+		set++
+	}
+	if len(s.Command) > 0 {
+		set++
+		if s.Command[0] == "" {
+			return fmt.Errorf("command cannot start with an empty program")
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of env, file, or command must be set")
+	}
+	return nil
+}
+
+// ValidateActionsValues checks variable and secret names and secret sources.
+func ValidateActionsValues(variables map[string]string, secrets map[string]SecretSource) error {
+	for name := range variables {
+		if err := ValidateActionsName(name); err != nil {
+			return fmt.Errorf("variable: %w", err)
+		}
+	}
+	for name, src := range secrets {
+		if err := ValidateActionsName(name); err != nil {
+			return fmt.Errorf("secret: %w", err)
+		}
+		if err := ValidateSecretSource(src); err != nil {
+			return fmt.Errorf("secret %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func ValidateEnvironment(e Environment) error {
+	if e.Name == "" {
+		return fmt.Errorf("environment name cannot be empty")
+	}
+	if len(e.Name) > 255 || !environmentPattern.MatchString(e.Name) {
+		return fmt.Errorf("environment name %q contains invalid characters", e.Name)
+	}
+	if e.WaitTimer < 0 || e.WaitTimer > 43200 {
+		return fmt.Errorf("environment %q: wait_timer must be 0-43200 minutes", e.Name)
+	}
+	if len(e.Reviewers) > 6 {
+		return fmt.Errorf("environment %q: at most 6 reviewers are allowed", e.Name)
+	}
+	for _, r := range e.Reviewers {
+		switch {
+		case r.Team != "" && r.User != "":
+			return fmt.Errorf("environment %q: reviewer must set team or user, not both", e.Name)
+		case r.Team != "":
+			if !teamRefPattern.MatchString(r.Team) {
+				return fmt.Errorf("environment %q: reviewer team %q is invalid", e.Name, r.Team)
+			}
+		case r.User != "":
+			if err := ValidateUserLogin(r.User); err != nil {
+				return fmt.Errorf("environment %q: %w", e.Name, err)
+			}
+		default:
+			return fmt.Errorf("environment %q: reviewer must set team or user", e.Name)
+		}
+	}
+	if err := validateEnum("deployment_branches", e.DeploymentBranches, deploymentBranches); err != nil {
+		return fmt.Errorf("environment %q: %w", e.Name, err)
+	}
+	if len(e.BranchPolicies) > 0 && e.DeploymentBranches != "custom" {
+		return fmt.Errorf("environment %q: branch_policies require deployment_branches: custom", e.Name)
+	}
+	for _, b := range e.BranchPolicies {
+		if strings.TrimSpace(b) == "" {
+			return fmt.Errorf("environment %q: branch_policies cannot contain empty patterns", e.Name)
+		}
+	}
+	if err := ValidateActionsValues(e.Variables, e.Secrets); err != nil {
+		return fmt.Errorf("environment %q: %w", e.Name, err)
+	}
+	return nil
+}
+
+func ValidateWebhook(w Webhook) error {
+	u, err := url.Parse(w.URL)
+	if w.URL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook url %q must be an http(s) URL", w.URL)
+	}
+	if w.Remove {
+		return nil
+	}
+	if err := validateEnum("webhook content_type", w.ContentType, webhookContentTypes); err != nil {
+		return err
+	}
+	for _, e := range w.Events {
+		if !webhookEventPattern.MatchString(e) {
+			return fmt.Errorf("webhook %s: event %q is invalid", w.URL, e)
+		}
+	}
+	if w.SecretEnv != "" && !envVarPattern.MatchString(w.SecretEnv) {
+		return fmt.Errorf("webhook %s: secret_env %q is not a valid environment variable name", w.URL, w.SecretEnv)
+	}
+	return nil
+}
+
+func ValidateAutolink(a Autolink) error {
+	if a.KeyPrefix == "" {
+		return fmt.Errorf("autolink key_prefix cannot be empty")
+	}
+	if !autolinkKeyPattern.MatchString(a.KeyPrefix) {
+		return fmt.Errorf("autolink key_prefix %q contains invalid characters", a.KeyPrefix)
+	}
+	if !strings.Contains(a.URLTemplate, "<num>") {
+		return fmt.Errorf("autolink %q: url_template must contain <num>", a.KeyPrefix)
+	}
+	if err := ValidateHomepage(strings.ReplaceAll(a.URLTemplate, "<num>", "1")); err != nil {
+		return fmt.Errorf("autolink %q: url_template %q must be an http(s) URL", a.KeyPrefix, a.URLTemplate)
+	}
+	return nil
+}
+
+func ValidateDeployKey(k DeployKey) error {
+	if strings.TrimSpace(k.Title) == "" {
+		return fmt.Errorf("deploy key title cannot be empty")
+	}
+	if _, err := RenderTemplate(k.Title, NewRepoVars("owner/repo")); err != nil {
+		return fmt.Errorf("deploy key %q title: %w", k.Title, err)
+	}
+	if (k.PublicKeyFile != "") == k.Generate {
+		return fmt.Errorf("deploy key %q needs exactly one of public_key_file or generate", k.Title)
+	}
+	if k.PrivateKeyPath != "" {
+		if !k.Generate {
+			return fmt.Errorf("deploy key %q: private_key_path requires generate: true", k.Title)
+		}
+		if _, err := RenderTemplate(k.PrivateKeyPath, NewRepoVars("owner/repo")); err != nil {
+			return fmt.Errorf("deploy key %q private_key_path: %w", k.Title, err)
+		}
+	}
+	return nil
+}
+
+func ValidatePages(p PagesConfig) error {
+	if !p.Enabled {
+		if p != (PagesConfig{}) {
+			return fmt.Errorf("pages settings require enabled: true")
+		}
+		return nil
+	}
+	if err := validateEnum("pages build_type", p.BuildType, pagesBuildTypes); err != nil {
+		return err
+	}
+	if p.IsWorkflow() {
+		if p.Branch != "" || p.Path != "" {
+			return fmt.Errorf("pages branch and path only apply to build_type: legacy")
+		}
+	} else {
+		if p.Workflow {
+			return fmt.Errorf("pages workflow requires build_type: workflow")
+		}
+		if p.Branch != "" {
+			if err := ValidateBranchName(p.Branch); err != nil {
+				return fmt.Errorf("pages: %w", err)
+			}
+		}
+		if err := validateEnum("pages path", p.Path, pagesPaths); err != nil {
+			return err
+		}
+	}
+	if p.CNAME != "" {
+		cname, err := RenderTemplate(p.CNAME, NewRepoVars("owner/repo"))
+		if err != nil {
+			return fmt.Errorf("pages cname: %w", err)
+		}
+		if !hostnamePattern.MatchString(cname) {
+			return fmt.Errorf("pages cname %q must be a lowercase hostname", p.CNAME)
+		}
+	}
+	return nil
+}
+
+func ValidateProject(p ProjectConfig) error {
+	if !p.IsSet() {
+		if p != (ProjectConfig{}) {
+			return fmt.Errorf("project settings require a number or title")
+		}
+		return nil
+	}
+	if p.Number != 0 && p.Title != "" {
+		return fmt.Errorf("project takes a number or a title, not both")
+	}
+	if p.Number < 0 {
+		return fmt.Errorf("project number must be positive")
+	}
+	if p.Owner != "" {
+		if err := ValidateUserLogin(p.Owner); err != nil {
+			return fmt.Errorf("project owner: %w", err)
+		}
+	}
+	if (p.Status != "" || p.StatusField != "") && !p.AddSeededIssues {
+		return fmt.Errorf("project status requires add_seeded_issues: true")
+	}
+	if p.StatusField != "" && p.Status == "" {
+		return fmt.Errorf("project status_field requires status")
+	}
+	return nil
+}
+
+// ValidateLocal checks the settings for a local clone. Remote URLs are
+// templates, rendered like homepage.
+func ValidateLocal(l LocalConfig) error {
+	if l.UserEmail != "" && (!strings.Contains(l.UserEmail, "@") || strings.ContainsAny(l.UserEmail, " \t\n")) {
+		return fmt.Errorf("local user_email %q is not an email address", l.UserEmail)
+	}
+	if strings.ContainsAny(l.SigningKey, "\n\r") {
+		return fmt.Errorf("local signing_key must be a single line")
+	}
+	for hook, src := range l.GitHooks {
+		if err := validateEnum("local git_hooks", hook, gitHookNames); err != nil {
+			return err
+		}
+		if src == "" {
+			return fmt.Errorf("local git hook %q has no template", hook)
+		}
+		if strings.Contains(src, "..") || filepath.IsAbs(src) {
+			return fmt.Errorf("local git hook %q: template %q contains path traversal", hook, src)
+		}
+	}
+	for remote, tmpl := range l.Remotes {
+		if !remoteNamePattern.MatchString(remote) {
+			return fmt.Errorf("local remote name %q contains invalid characters", remote)
+		}
+		if remote == "origin" {
+			return fmt.Errorf("local remotes can't replace origin")
+		}
+		url, err := RenderTemplate(tmpl, NewRepoVars("owner/repo"))
+		if err != nil {
+			return fmt.Errorf("local remote %q: %w", remote, err)
+		}
+		if strings.TrimSpace(url) == "" {
+			return fmt.Errorf("local remote %q has no URL", remote)
+		}
+	}
+	return validateCommands("local post_clone", l.PostClone)
+}
+
+// ValidateHooks checks that every hook command names a program and every
+// passed-through variable is a bare name.
+func ValidateHooks(h HooksConfig) error {
+	if err := validateCommands("hooks pre_create", h.PreCreate); err != nil {
+		return err
+	}
+	if err := validateCommands("hooks post_create", h.PostCreate); err != nil {
+		return err
+	}
+	if err := validateCommands("hooks post_apply", h.PostApply); err != nil {
+		return err
+	}
+	for _, name := range h.PassEnv {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("hooks pass_env %q is not a variable name", name)
+		}
+	}
+	return nil
+}
+
+func validateCommands(field string, cmds [][]string) error {
+	for i, argv := range cmds {
+		if len(argv) == 0 || argv[0] == "" {
+			return fmt.Errorf("%s command %d is empty", field, i+1)
+		}
+	}
+	return nil
+}
+
+// maxDiscussionCategories is GitHub's limit on categories per repo.
+const maxDiscussionCategories = 25
+
+// ValidateDiscussions checks discussion categories. They only make sense
+// when the profile turns Discussions on.
+func ValidateDiscussions(d DiscussionsConfig, settings RepoSettings) error {
+	if len(d.Categories) == 0 {
+		return nil
+	}
+	if !isTrue(settings.HasDiscussions) {
+		return fmt.Errorf("discussions categories require settings.has_discussions: true")
+	}
+	if len(d.Categories) > maxDiscussionCategories {
+		return fmt.Errorf("cannot have more than %d discussion categories", maxDiscussionCategories)
+	}
+	names := make(map[string]bool)
+	for _, c := range d.Categories {
+		if strings.TrimSpace(c.Name) == "" {
+			return fmt.Errorf("discussion category name cannot be empty")
+		}
+		key := strings.ToLower(c.Name)
+		if names[key] {
+			return fmt.Errorf("discussion category %q is listed more than once", c.Name)
+		}
+		names[key] = true
+		if c.Emoji != "" && !emojiPattern.MatchString(c.Emoji) {
+			return fmt.Errorf("discussion category %q emoji %q must be a shortcode like :rocket:", c.Name, c.Emoji)
+		}
+		if err := validateEnum("discussion category format", c.Format, discussionFormats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxPinnedIssues is GitHub's limit on pinned issues per repo.
+const maxPinnedIssues = 3
+
+// ValidateSeed checks seed milestones and issues. Issue labels must be
+// defined in labels and issue milestones in the seed milestones, and
+// settings must not turn issues off.
+func ValidateSeed(s SeedConfig, labels []Label, settings RepoSettings) error {
+	if (len(s.Milestones) > 0 || len(s.Issues) > 0) && isFalse(settings.HasIssues) {
+		return fmt.Errorf("seed milestones and issues require issues; settings.has_issues is false")
+	}
+	milestones := make(map[string]bool)
+	for _, m := range s.Milestones {
+		if strings.TrimSpace(m.Title) == "" {
+			return fmt.Errorf("seed milestone title cannot be empty")
+		}
+		if milestones[m.Title] {
+			return fmt.Errorf("seed milestone %q is listed more than once", m.Title)
+		}
+		milestones[m.Title] = true
+		if m.DueIn != "" {
+			if _, err := ParseDueIn(m.DueIn); err != nil {
+				return fmt.Errorf("seed milestone %q: %w", m.Title, err)
+			}
+		}
+	}
+
+	labelNames := make(map[string]bool)
+	for _, l := range labels {
+		labelNames[strings.ToLower(l.Name)] = true
+	}
+	pinned := 0
+	for _, i := range s.Issues {
+		if strings.TrimSpace(i.Title) == "" {
+			return fmt.Errorf("seed issue title cannot be empty")
+		}
+		if _, err := RenderTemplate(i.Body, NewRepoVars("owner/repo")); err != nil {
+			return fmt.Errorf("seed issue %q body: %w", i.Title, err)
+		}
+		for _, l := range i.Labels {
+			if !labelNames[strings.ToLower(l)] {
+				return fmt.Errorf("seed issue %q: label %q is not defined in the profile's labels", i.Title, l)
+			}
+		}
+		for _, a := range i.Assignees {
+			if err := ValidateUserLogin(a); err != nil {
+				return fmt.Errorf("seed issue %q: %w", i.Title, err)
+			}
+		}
+		if i.Milestone != "" && !milestones[i.Milestone] {
+			return fmt.Errorf("seed issue %q: milestone %q is not defined in seed milestones", i.Title, i.Milestone)
+		}
+		if i.Pin {
+			pinned++
+		}
+	}
+	if pinned > maxPinnedIssues {
+		return fmt.Errorf("at most %d seed issues can be pinned", maxPinnedIssues)
+	}
+	return nil
+}
+
 func ValidateDescription(desc string) error {
 	if len(desc) > 350 {
 		return fmt.Errorf("description cannot exceed 350 characters")
@@ -91,10 +645,176 @@ func ValidateDescription(desc string) error {
 	return nil
 }
 
+var (
+	squashMergeCommitTitles   = []string{"PR_TITLE", "COMMIT_OR_PR_TITLE"}
+	squashMergeCommitMessages = []string{"PR_BODY", "COMMIT_MESSAGES", "BLANK"}
+	mergeCommitTitles         = []string{"PR_TITLE", "MERGE_MESSAGE"}
+	mergeCommitMessages       = []string{"PR_BODY", "PR_TITLE", "BLANK"}
+)
+
+func validateEnum(field, value string, allowed []string) error {
+	if value == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q must be one of: %s", field, value, strings.Join(allowed, ", "))
+}
+
+// isFalse reports whether b is explicitly set to false.
+func isFalse(b *bool) bool { return b != nil && !*b }
+
+// isTrue reports whether b is explicitly set to true.
+func isTrue(b *bool) bool { return b != nil && *b }
+
+// ValidateRepoSettings checks enum values and rejects combinations the
+// GitHub API would refuse.
+func ValidateRepoSettings(s RepoSettings) error {
+	if err := validateEnum("squash_merge_commit_title", s.SquashMergeCommitTitle, squashMergeCommitTitles); err != nil {
+		return err
+	}
+	if err := validateEnum("squash_merge_commit_message", s.SquashMergeCommitMessage, squashMergeCommitMessages); err != nil {
+		return err
+	}
+	if err := validateEnum("merge_commit_title", s.MergeCommitTitle, mergeCommitTitles); err != nil {
+		return err
+	}
+	if err := validateEnum("merge_commit_message", s.MergeCommitMessage, mergeCommitMessages); err != nil {
+		return err
+	}
+
+	if isFalse(s.AllowSquashMerge) && isFalse(s.AllowMergeCommit) && isFalse(s.AllowRebaseMerge) {
+		return fmt.Errorf("at least one of allow_squash_merge, allow_merge_commit, allow_rebase_merge must be enabled")
+	}
+	if isFalse(s.AllowSquashMerge) && (s.SquashMergeCommitTitle != "" || s.SquashMergeCommitMessage != "") {
+		return fmt.Errorf("squash_merge_commit_title/message require allow_squash_merge")
+	}
+	if isFalse(s.AllowMergeCommit) && (s.MergeCommitTitle != "" || s.MergeCommitMessage != "") {
+		return fmt.Errorf("merge_commit_title/message require allow_merge_commit")
+	}
+
+	// GitHub only accepts the "default message" pairings for these titles.
+	if s.SquashMergeCommitTitle == "COMMIT_OR_PR_TITLE" && s.SquashMergeCommitMessage != "" && s.SquashMergeCommitMessage != "COMMIT_MESSAGES" {
+		return fmt.Errorf("squash_merge_commit_title COMMIT_OR_PR_TITLE requires squash_merge_commit_message COMMIT_MESSAGES")
+	}
+	if s.MergeCommitTitle == "MERGE_MESSAGE" && s.MergeCommitMessage != "" && s.MergeCommitMessage != "PR_TITLE" {
+		return fmt.Errorf("merge_commit_title MERGE_MESSAGE requires merge_commit_message PR_TITLE")
+	}
+	if s.MergeCommitTitle == "PR_TITLE" && s.MergeCommitMessage == "PR_TITLE" {
+		return fmt.Errorf("merge_commit_title PR_TITLE requires merge_commit_message PR_BODY or BLANK")
+	}
+	return nil
+}
+
+// ValidateSecurity rejects enabling a feature whose prerequisite is disabled.
+func ValidateSecurity(s SecurityConfig) error {
+	if isTrue(s.AutomatedSecurityFixes) && isFalse(s.VulnerabilityAlerts) {
+		return fmt.Errorf("automated_security_fixes requires vulnerability_alerts")
+	}
+	if isTrue(s.SecretScanningPushProtection) && isFalse(s.SecretScanning) {
+		return fmt.Errorf("secret_scanning_push_protection requires secret_scanning")
+	}
+	return nil
+}
+
 func ValidateProfile(name string, p Profile) error {
 	if err := ValidateProfileName(name); err != nil {
 		return err
 	}
+	if p.TemplateRepo != "" {
+		if err := ValidateNWO(p.TemplateRepo); err != nil {
+			return fmt.Errorf("profile %q template_repo: %w", name, err)
+		}
+	}
+	if err := ValidateRepoSettings(p.Settings); err != nil {
+		return fmt.Errorf("profile %q settings: %w", name, err)
+	}
+	if err := ValidateSecurity(p.Security); err != nil {
+		return fmt.Errorf("profile %q security: %w", name, err)
+	}
+	if err := ValidateTopics(p.Topics.Items); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := ValidateHomepageTemplate(p.Homepage); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := ValidateCustomProperties(p.CustomProperties); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := ValidateAccess(p.Access); err != nil {
+		return fmt.Errorf("profile %q access: %w", name, err)
+	}
+	if err := ValidateActionsPolicy(p.Actions); err != nil {
+		return fmt.Errorf("profile %q actions: %w", name, err)
+	}
+	if err := ValidateActionsValues(p.Actions.Variables, p.Actions.Secrets); err != nil {
+		return fmt.Errorf("profile %q actions: %w", name, err)
+	}
+	if p.Fork.SyncUpstream && isFalse(p.Actions.Enabled) {
+		return fmt.Errorf("profile %q: fork.sync_upstream needs Actions; actions.enabled is false", name)
+	}
+	if err := ValidatePages(p.Pages); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := ValidateSeed(p.Seed, p.Labels.Items, p.Settings); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := ValidateDiscussions(p.Discussions, p.Settings); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := ValidateProject(p.Project); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := ValidateLocal(p.Local); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := ValidateHooks(p.Hooks); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	hooks := make(map[string]bool)
+	for _, w := range p.Webhooks {
+		if err := ValidateWebhook(w); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		if hooks[w.URL] {
+			return fmt.Errorf("profile %q: webhook %q is listed more than once", name, w.URL)
+		}
+		hooks[w.URL] = true
+	}
+	prefixes := make(map[string]bool)
+	for _, a := range p.Autolinks {
+		if err := ValidateAutolink(a); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		key := strings.ToLower(a.KeyPrefix)
+		if prefixes[key] {
+			return fmt.Errorf("profile %q: autolink %q is listed more than once", name, a.KeyPrefix)
+		}
+		prefixes[key] = true
+	}
+	keyTitles := make(map[string]bool)
+	for _, k := range p.DeployKeys {
+		if err := ValidateDeployKey(k); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		if keyTitles[k.Title] {
+			return fmt.Errorf("profile %q: deploy key %q is listed more than once", name, k.Title)
+		}
+		keyTitles[k.Title] = true
+	}
+	envs := make(map[string]bool)
+	for _, e := range p.Environments {
+		if err := ValidateEnvironment(e); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		if envs[strings.ToLower(e.Name)] {
+			return fmt.Errorf("profile %q: environment %q is listed more than once", name, e.Name)
+		}
+		envs[strings.ToLower(e.Name)] = true
+	}
 	for _, l := range p.Labels.Items {
 		if err := ValidateLabelName(l.Name); err != nil {
 			return fmt.Errorf("profile %q: %w", name, err)
@@ -172,6 +172,525 @@ func TestValidateBranchName(t *testing.T) {
 	}
 }
 
+func TestValidateTopic(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"valid", "go", false},
+		{"valid with hyphen", "github-cli", false},
+		{"empty", "", true},
+		{"uppercase", "Go", true},
+		{"leading hyphen", "-go", true},
+		{"spaces", "gh cli", true},
+		{"too long", strings.Repeat("a", 51), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTopic(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTopic(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHomepageTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"plain URL", "https://example.com", false},
+		{"templated", "https://{{.Owner}}.github.io/{{.Name}}", false},
+		{"no scheme", "example.com", true},
+		{"bad scheme", "ftp://example.com", true},
+		{"unknown field", "https://{{.Team}}.example.com", true},
+		{"unclosed action", "https://{{.Owner", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHomepageTemplate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHomepageTemplate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCustomProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   map[string]PropertyValue
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", map[string]PropertyValue{"team": {"platform"}, "data-classification": {"pii", "internal"}}, false},
+		{"invalid name", map[string]PropertyValue{"my team": {"platform"}}, true},
+		{"no value", map[string]PropertyValue{"team": {}}, true},
+		{"empty value", map[string]PropertyValue{"team": {""}}, true},
+		{"value too long", map[string]PropertyValue{"team": {strings.Repeat("a", 76)}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomProperties(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCustomProperties() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAccess(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   AccessConfig
+		wantErr bool
+	}{
+		{"empty", AccessConfig{}, false},
+		{"valid", AccessConfig{
+			Teams: []TeamAccess{{Team: "@acme/platform", Permission: "maintain"}, {Team: "security", Permission: "triage"}},
+			Users: []UserAccess{{User: "octocat", Permission: "push"}},
+		}, false},
+		{"bad permission", AccessConfig{Users: []UserAccess{{User: "octocat", Permission: "write"}}}, true},
+		{"missing permission", AccessConfig{Teams: []TeamAccess{{Team: "platform"}}}, true},
+		{"bad team ref", AccessConfig{Teams: []TeamAccess{{Team: "acme/platform/extra", Permission: "pull"}}}, true},
+		{"bad login", AccessConfig{Users: []UserAccess{{User: "not a user", Permission: "pull"}}}, true},
+		{"duplicate team", AccessConfig{Teams: []TeamAccess{{Team: "acme/platform", Permission: "pull"}, {Team: "@acme/platform", Permission: "admin"}}}, true},
+		{"duplicate user", AccessConfig{Users: []UserAccess{{User: "octocat", Permission: "pull"}, {User: "Octocat", Permission: "push"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAccess(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAccess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDeployKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   DeployKey
+		wantErr bool
+	}{
+		{"from file", DeployKey{Title: "config-sync", PublicKeyFile: "~/.ssh/sync.pub"}, false},
+		{"generated", DeployKey{Title: "config-sync {{.Name}}", Generate: true, PrivateKeyPath: "~/.ssh/deploy/{{.Name}}"}, false},
+		{"generated and printed", DeployKey{Title: "ci", Generate: true}, false},
+		{"empty title", DeployKey{Generate: true}, true},
+		{"bad title template", DeployKey{Title: "{{.Repo}}", Generate: true}, true},
+		{"no source", DeployKey{Title: "ci"}, true},
+		{"both sources", DeployKey{Title: "ci", PublicKeyFile: "ci.pub", Generate: true}, true},
+		{"private path with file", DeployKey{Title: "ci", PublicKeyFile: "ci.pub", PrivateKeyPath: "ci"}, true},
+		{"bad private path template", DeployKey{Title: "ci", Generate: true, PrivateKeyPath: "{{.Nope}}"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDeployKey(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDeployKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePages(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   PagesConfig
+		wantErr bool
+	}{
+		{"disabled", PagesConfig{}, false},
+		{"workflow", PagesConfig{Enabled: true, Workflow: true}, false},
+		{"legacy", PagesConfig{Enabled: true, BuildType: "legacy", Branch: "gh-pages", Path: "/"}, false},
+		{"templated cname", PagesConfig{Enabled: true, CNAME: "{{.Name}}.docs.acme.com"}, false},
+		{"settings while disabled", PagesConfig{CNAME: "docs.acme.com"}, true},
+		{"bad build type", PagesConfig{Enabled: true, BuildType: "jekyll"}, true},
+		{"branch with workflow", PagesConfig{Enabled: true, Branch: "gh-pages"}, true},
+		{"workflow file with legacy", PagesConfig{Enabled: true, BuildType: "legacy", Workflow: true}, true},
+		{"bad path", PagesConfig{Enabled: true, BuildType: "legacy", Path: "/site"}, true},
+		{"bad cname", PagesConfig{Enabled: true, CNAME: "https://docs.acme.com"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePages(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   ProjectConfig
+		wantErr bool
+	}{
+		{"unset", ProjectConfig{}, false},
+		{"by number", ProjectConfig{Number: 4}, false},
+		{"by title with status", ProjectConfig{Owner: "acme", Title: "Roadmap", AddSeededIssues: true, Status: "Todo"}, false},
+		{"settings without project", ProjectConfig{Owner: "acme"}, true},
+		{"number and title", ProjectConfig{Number: 4, Title: "Roadmap"}, true},
+		{"negative number", ProjectConfig{Number: -1}, true},
+		{"bad owner", ProjectConfig{Owner: "-acme", Number: 4}, true},
+		{"status without issues", ProjectConfig{Number: 4, Status: "Todo"}, true},
+		{"field without status", ProjectConfig{Number: 4, AddSeededIssues: true, StatusField: "Stage"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateProject(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLocal(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   LocalConfig
+		wantErr bool
+	}{
+		{"unset", LocalConfig{}, false},
+		{"full", LocalConfig{
+			UserEmail:  "me@work.example",
+			SigningKey: "ABCD1234",
+			GitHooks:   map[string]string{"pre-commit": "pre-commit.sh"},
+			Remotes:    map[string]string{"mirror": "git@gitlab.example:{{.NWO}}.git"},
+			PostClone:  [][]string{{"make", "setup"}},
+		}, false},
+		{"bad email", LocalConfig{UserEmail: "me"}, true},
+		{"multiline key", LocalConfig{SigningKey: "a\nb"}, true},
+		{"unknown hook", LocalConfig{GitHooks: map[string]string{"pre-receive": "x.sh"}}, true},
+		{"hook traversal", LocalConfig{GitHooks: map[string]string{"pre-commit": "../x.sh"}}, true},
+		{"origin remote", LocalConfig{Remotes: map[string]string{"origin": "https://example.com/x.git"}}, true},
+		{"bad remote name", LocalConfig{Remotes: map[string]string{"my remote": "https://example.com/x.git"}}, true},
+		{"bad remote template", LocalConfig{Remotes: map[string]string{"mirror": "{{.Team}}"}}, true},
+		{"empty command", LocalConfig{PostClone: [][]string{{}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLocal(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLocal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   HooksConfig
+		wantErr bool
+	}{
+		{"unset", HooksConfig{}, false},
+		{"commands", HooksConfig{
+			PreCreate:  [][]string{{"catalog", "check"}},
+			PostCreate: [][]string{{"go", "mod", "init", "example.com/widget"}},
+			PostApply:  [][]string{{"catalog", "register"}},
+		}, false},
+		{"empty post_create", HooksConfig{PostCreate: [][]string{{"go", "mod", "tidy"}, {}}}, true},
+		{"no program", HooksConfig{PostApply: [][]string{{"", "register"}}}, true},
+		{"pass_env", HooksConfig{PassEnv: []string{"GH_TOKEN", "NPM_TOKEN"}}, false},
+		{"pass_env assignment", HooksConfig{PassEnv: []string{"GH_TOKEN=x"}}, true},
+		{"pass_env empty", HooksConfig{PassEnv: []string{""}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHooks(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDiscussions(t *testing.T) {
+	on, off := true, false
+	enabled := RepoSettings{HasDiscussions: &on}
+	tests := []struct {
+		name     string
+		input    DiscussionsConfig
+		settings RepoSettings
+		wantErr  bool
+	}{
+		{"empty", DiscussionsConfig{}, RepoSettings{}, false},
+		{"valid", DiscussionsConfig{Categories: []DiscussionCategory{{Name: "Q&A", Emoji: ":pray:", Format: "qa"}, {Name: "RFCs"}}}, enabled, false},
+		{"discussions off", DiscussionsConfig{Categories: []DiscussionCategory{{Name: "RFCs"}}}, RepoSettings{HasDiscussions: &off}, true},
+		{"discussions unset", DiscussionsConfig{Categories: []DiscussionCategory{{Name: "RFCs"}}}, RepoSettings{}, true},
+		{"empty name", DiscussionsConfig{Categories: []DiscussionCategory{{Name: " "}}}, enabled, true},
+		{"duplicate name", DiscussionsConfig{Categories: []DiscussionCategory{{Name: "Ideas"}, {Name: "ideas"}}}, enabled, true},
+		{"bad emoji", DiscussionsConfig{Categories: []DiscussionCategory{{Name: "Ideas", Emoji: "bulb"}}}, enabled, true},
+		{"bad format", DiscussionsConfig{Categories: []DiscussionCategory{{Name: "Polls", Format: "poll"}}}, enabled, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDiscussions(tt.input, tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDiscussions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSeed(t *testing.T) {
+	labels := []Label{{Name: "bug"}, {Name: "good first issue"}}
+	ms := []SeedMilestone{{Title: "v0.1", DueIn: "6w"}}
+	off := false
+	tests := []struct {
+		name     string
+		input    SeedConfig
+		settings RepoSettings
+		wantErr  bool
+	}{
+		{"empty", SeedConfig{}, RepoSettings{HasIssues: &off}, false},
+		{"valid", SeedConfig{Milestones: ms, Issues: []SeedIssue{{Title: "Set up CI", Body: "For {{.NWO}}", Labels: []string{"Good First Issue"}, Milestone: "v0.1", Pin: true}}}, RepoSettings{}, false},
+		{"empty milestone title", SeedConfig{Milestones: []SeedMilestone{{Title: " "}}}, RepoSettings{}, true},
+		{"duplicate milestone", SeedConfig{Milestones: []SeedMilestone{{Title: "v0.1"}, {Title: "v0.1"}}}, RepoSettings{}, true},
+		{"bad due_in", SeedConfig{Milestones: []SeedMilestone{{Title: "v0.1", DueIn: "2 weeks"}}}, RepoSettings{}, true},
+		{"empty issue title", SeedConfig{Issues: []SeedIssue{{Body: "x"}}}, RepoSettings{}, true},
+		{"bad body template", SeedConfig{Issues: []SeedIssue{{Title: "x", Body: "{{.Nope}}"}}}, RepoSettings{}, true},
+		{"unknown label", SeedConfig{Issues: []SeedIssue{{Title: "x", Labels: []string{"feature"}}}}, RepoSettings{}, true},
+		{"bad assignee", SeedConfig{Issues: []SeedIssue{{Title: "x", Assignees: []string{"-bad"}}}}, RepoSettings{}, true},
+		{"unknown milestone", SeedConfig{Issues: []SeedIssue{{Title: "x", Milestone: "v9"}}}, RepoSettings{}, true},
+		{"issues disabled", SeedConfig{Issues: []SeedIssue{{Title: "x"}}}, RepoSettings{HasIssues: &off}, true},
+		{"milestones with issues disabled", SeedConfig{Milestones: ms}, RepoSettings{HasIssues: &off}, true},
+		{"too many pinned", SeedConfig{Issues: []SeedIssue{{Title: "a", Pin: true}, {Title: "b", Pin: true}, {Title: "c", Pin: true}, {Title: "d", Pin: true}}}, RepoSettings{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSeed(tt.input, labels, tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAutolink(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   Autolink
+		wantErr bool
+	}{
+		{"valid", Autolink{KeyPrefix: "JIRA-", URLTemplate: "https://acme.atlassian.net/browse/JIRA-<num>"}, false},
+		{"empty prefix", Autolink{URLTemplate: "https://example.com/<num>"}, true},
+		{"prefix with space", Autolink{KeyPrefix: "MY TICKET-", URLTemplate: "https://example.com/<num>"}, true},
+		{"missing num", Autolink{KeyPrefix: "INC-", URLTemplate: "https://example.com/"}, true},
+		{"not a URL", Autolink{KeyPrefix: "INC-", URLTemplate: "incidents/<num>"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAutolink(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAutolink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   Webhook
+		wantErr bool
+	}{
+		{"minimal", Webhook{URL: "https://ci.example.com/hook"}, false},
+		{"full", Webhook{URL: "https://chatops.example.com/github", ContentType: "form", Events: []string{"push", "pull_request"}, SecretEnv: "CHATOPS_SECRET"}, false},
+		{"wildcard event", Webhook{URL: "https://ci.example.com/hook", Events: []string{"*"}}, false},
+		{"remove", Webhook{URL: "https://old.example.com/hook", Remove: true}, false},
+		{"no url", Webhook{}, true},
+		{"bad scheme", Webhook{URL: "ftp://ci.example.com"}, true},
+		{"bad content type", Webhook{URL: "https://ci.example.com/hook", ContentType: "xml"}, true},
+		{"bad event", Webhook{URL: "https://ci.example.com/hook", Events: []string{"Pull Request"}}, true},
+		{"bad secret env", Webhook{URL: "https://ci.example.com/hook", SecretEnv: "MY-SECRET"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhook(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   Environment
+		wantErr bool
+	}{
+		{"minimal", Environment{Name: "staging"}, false},
+		{"full", Environment{
+			Name:               "production",
+			WaitTimer:          30,
+			Reviewers:          []Reviewer{{Team: "@acme/platform"}, {User: "octocat"}},
+			DeploymentBranches: "custom",
+			BranchPolicies:     []string{"main", "release/*"},
+			Variables:          map[string]string{"DEPLOY_ENV": "prod"},
+			Secrets:            map[string]SecretSource{"DEPLOY_TOKEN": {Env: "PROD_TOKEN"}},
+		}, false},
+		{"empty name", Environment{}, true},
+		{"bad name", Environment{Name: "prod/eu"}, true},
+		{"negative wait timer", Environment{Name: "production", WaitTimer: -1}, true},
+		{"wait timer too long", Environment{Name: "production", WaitTimer: 43201}, true},
+		{"reviewer without target", Environment{Name: "production", Reviewers: []Reviewer{{}}}, true},
+		{"reviewer with both", Environment{Name: "production", Reviewers: []Reviewer{{Team: "platform", User: "octocat"}}}, true},
+		{"bad deployment branches", Environment{Name: "production", DeploymentBranches: "main"}, true},
+		{"policies without custom", Environment{Name: "production", DeploymentBranches: "protected", BranchPolicies: []string{"main"}}, true},
+		{"bad secret", Environment{Name: "production", Secrets: map[string]SecretSource{"TOKEN": {}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEnvironment(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEnvironment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateActionsPolicy(t *testing.T) {
+	f := false
+	tr := true
+	tests := []struct {
+		name    string
+		input   ActionsConfig
+		wantErr bool
+	}{
+		{"empty", ActionsConfig{}, false},
+		{"hardened", ActionsConfig{AllowedActions: "selected", GithubOwnedAllowed: &tr, PatternsAllowed: []string{"docker/*"}, DefaultWorkflowPermissions: "read", CanApprovePullRequestReviews: &f}, false},
+		{"disabled", ActionsConfig{Enabled: &f}, false},
+		{"bad allowed_actions", ActionsConfig{AllowedActions: "none"}, true},
+		{"bad token permission", ActionsConfig{DefaultWorkflowPermissions: "write-all"}, true},
+		{"policy while disabled", ActionsConfig{Enabled: &f, DefaultWorkflowPermissions: "read"}, true},
+		{"patterns without selected", ActionsConfig{AllowedActions: "all", PatternsAllowed: []string{"docker/*"}}, true},
+		{"empty pattern", ActionsConfig{AllowedActions: "selected", PatternsAllowed: []string{" "}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateActionsPolicy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateActionsPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateActionsName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"valid", "REGISTRY_URL", false},
+		{"leading underscore", "_TOKEN", false},
+		{"empty", "", true},
+		{"leading digit", "1TOKEN", true},
+		{"hyphen", "MY-TOKEN", true},
+		{"reserved prefix", "GITHUB_TOKEN", true},
+		{"reserved prefix lowercase", "github_token", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateActionsName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateActionsName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSecretSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   SecretSource
+		wantErr bool
+	}{
+		{"env", SecretSource{Env: "TOKEN"}, false},
+		{"file", SecretSource{File: "~/.secrets/token"}, false},
+		{"command", SecretSource{Command: []string{"op", "read", "op://vault/item"}}, false},
+		{"none", SecretSource{}, true},
+		{"two sources", SecretSource{Env: "TOKEN", File: "token"}, true},
+		{"empty program", SecretSource{Command: []string{""}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecretSource(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSecretSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRepoSettings(t *testing.T) {
+	f := false
+	tr := true
+	tests := []struct {
+		name    string
+		input   RepoSettings
+		wantErr bool
+	}{
+		{"empty", RepoSettings{}, false},
+		{"valid squash title", RepoSettings{SquashMergeCommitTitle: "PR_TITLE", SquashMergeCommitMessage: "PR_BODY"}, false},
+		{"valid default squash message", RepoSettings{SquashMergeCommitTitle: "COMMIT_OR_PR_TITLE", SquashMergeCommitMessage: "COMMIT_MESSAGES"}, false},
+		{"valid merge commit", RepoSettings{MergeCommitTitle: "MERGE_MESSAGE", MergeCommitMessage: "PR_TITLE"}, false},
+		{"squash title typo", RepoSettings{SquashMergeCommitTitle: "PR_TITEL"}, true},
+		{"lowercase squash message", RepoSettings{SquashMergeCommitMessage: "pr_body"}, true},
+		{"invalid merge title", RepoSettings{MergeCommitTitle: "COMMIT_OR_PR_TITLE"}, true},
+		{"invalid merge message", RepoSettings{MergeCommitMessage: "COMMIT_MESSAGES"}, true},
+		{"all merge methods disabled", RepoSettings{AllowSquashMerge: &f, AllowMergeCommit: &f, AllowRebaseMerge: &f}, true},
+		{"one merge method enabled", RepoSettings{AllowSquashMerge: &tr, AllowMergeCommit: &f, AllowRebaseMerge: &f}, false},
+		{"squash format without squash", RepoSettings{AllowSquashMerge: &f, SquashMergeCommitTitle: "PR_TITLE"}, true},
+		{"merge format without merge commit", RepoSettings{AllowMergeCommit: &f, MergeCommitMessage: "PR_BODY"}, true},
+		{"mismatched squash pairing", RepoSettings{SquashMergeCommitTitle: "COMMIT_OR_PR_TITLE", SquashMergeCommitMessage: "PR_BODY"}, true},
+		{"mismatched merge pairing", RepoSettings{MergeCommitTitle: "MERGE_MESSAGE", MergeCommitMessage: "BLANK"}, true},
+		{"valid PR title merge", RepoSettings{MergeCommitTitle: "PR_TITLE", MergeCommitMessage: "PR_BODY"}, false},
+		{"PR title merge with PR title message", RepoSettings{MergeCommitTitle: "PR_TITLE", MergeCommitMessage: "PR_TITLE"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRepoSettings(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRepoSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSecurity(t *testing.T) {
+	f := false
+	tr := true
+	tests := []struct {
+		name    string
+		input   SecurityConfig
+		wantErr bool
+	}{
+		{"empty", SecurityConfig{}, false},
+		{"all enabled", SecurityConfig{VulnerabilityAlerts: &tr, AutomatedSecurityFixes: &tr, SecretScanning: &tr, SecretScanningPushProtection: &tr, PrivateVulnerabilityReporting: &tr}, false},
+		{"fixes with alerts unset", SecurityConfig{AutomatedSecurityFixes: &tr}, false},
+		{"fixes without alerts", SecurityConfig{VulnerabilityAlerts: &f, AutomatedSecurityFixes: &tr}, true},
+		{"push protection without scanning", SecurityConfig{SecretScanning: &f, SecretScanningPushProtection: &tr}, true},
+		{"both disabled", SecurityConfig{SecretScanning: &f, SecretScanningPushProtection: &f}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecurity(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSecurity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateProfile(t *testing.T) {
 	t.Run("valid profile", func(t *testing.T) {
 		p := Profile{
@@ -226,4 +745,19 @@ func TestValidateProfile(t *testing.T) {
 			t.Error("expected error for empty boilerplate src")
 		}
 	})
+
+	t.Run("invalid template repo", func(t *testing.T) {
+		p := Profile{TemplateRepo: "just-a-name"}
+		if err := ValidateProfile("oss", p); err == nil {
+			t.Error("expected error for template_repo without an owner")
+		}
+	})
+
+	t.Run("sync upstream with actions disabled", func(t *testing.T) {
+		off := false
+		p := Profile{Fork: ForkConfig{SyncUpstream: true}, Actions: ActionsConfig{Enabled: &off}}
+		if err := ValidateProfile("oss", p); err == nil {
+			t.Error("expected error for fork.sync_upstream with actions.enabled: false")
+		}
+	})
 }
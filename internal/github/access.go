@@ -0,0 +1,151 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// accessPrunePlan returns the existing users and teams ("org/slug") that
+// are not granted by cfg and should be removed. Logins in keep, such as the
+// repo owner and the authenticated user, are never removed.
+func accessPrunePlan(owner string, cfg config.AccessConfig, users, teams, keep []string) (removeUsers, removeTeams []string) {
+	wantUsers := make(map[string]bool)
+	for _, u := range cfg.Users {
+		wantUsers[strings.ToLower(u.User)] = true
+	}
+	for _, k := range keep {
+		wantUsers[strings.ToLower(k)] = true
+	}
+	wantTeams := make(map[string]bool)
+	for _, t := range cfg.Teams {
+		org, slug := t.OrgSlug(owner)
+		wantTeams[strings.ToLower(org+"/"+slug)] = true
+	}
+
+	for _, u := range users {
+		if !wantUsers[strings.ToLower(u)] {
+			removeUsers = append(removeUsers, u)
+		}
+	}
+	for _, t := range teams {
+		if !wantTeams[strings.ToLower(t)] {
+			removeTeams = append(removeTeams, t)
+		}
+	}
+	return
+}
+
+func (c *Client) GrantTeam(nwo, org, slug, permission string) error {
+	endpoint := fmt.Sprintf("orgs/%s/teams/%s/repos/%s", org, slug, nwo)
+	if _, err := c.api("PUT", endpoint, map[string]string{"permission": permission}); err != nil {
+		return fmt.Errorf("granting team %s/%s: %w", org, slug, err)
+	}
+	return nil
+}
+
+func (c *Client) RemoveTeam(nwo, org, slug string) error {
+	endpoint := fmt.Sprintf("orgs/%s/teams/%s/repos/%s", org, slug, nwo)
+	if _, err := c.api("DELETE", endpoint, nil); err != nil {
+		return fmt.Errorf("removing team %s/%s: %w", org, slug, err)
+	}
+	return nil
+}
+
+func (c *Client) GrantUser(nwo, user, permission string) error {
+	endpoint := fmt.Sprintf("repos/%s/collaborators/%s", nwo, user)
+	if _, err := c.api("PUT", endpoint, map[string]string{"permission": permission}); err != nil {
+		return fmt.Errorf("granting user %s: %w", user, err)
+	}
+	return nil
+}
+
+func (c *Client) RemoveUser(nwo, user string) error {
+	endpoint := fmt.Sprintf("repos/%s/collaborators/%s", nwo, user)
+	if _, err := c.api("DELETE", endpoint, nil); err != nil {
+		return fmt.Errorf("removing user %s: %w", user, err)
+	}
+	return nil
+}
+
+// ListCollaborators returns the logins of direct collaborators.
+func (c *Client) ListCollaborators(nwo string) ([]string, error) {
+	users, err := c.apiList(fmt.Sprintf("repos/%s/collaborators?affiliation=direct", nwo), ".[].login")
+	if err != nil {
+		return nil, fmt.Errorf("listing collaborators: %w", err)
+	}
+	return users, nil
+}
+
+// ListTeams returns the teams with access to the repo as "org/slug".
+func (c *Client) ListTeams(nwo string) ([]string, error) {
+	teams, err := c.apiList(fmt.Sprintf("repos/%s/teams", nwo), `.[] | .organization.login + "/" + .slug`)
+	if err != nil {
+		return nil, fmt.Errorf("listing teams: %w", err)
+	}
+	return teams, nil
+}
+
+// SyncAccess grants the configured teams and users their permission and,
+// when cfg.Prune is set, removes direct collaborators and teams not listed.
+func (c *Client) SyncAccess(nwo string, cfg config.AccessConfig) (granted int, removed int, errs []error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		errs = append(errs, fmt.Errorf("invalid nwo: %w", err))
+		return
+	}
+	owner := config.NewRepoVars(nwo).Owner
+
+	for _, t := range cfg.Teams {
+		org, slug := t.OrgSlug(owner)
+		if err := c.GrantTeam(nwo, org, slug, t.Permission); err != nil {
+			errs = append(errs, err)
+		} else {
+			granted++
+		}
+	}
+	for _, u := range cfg.Users {
+		if err := c.GrantUser(nwo, u.User, u.Permission); err != nil {
+			errs = append(errs, err)
+		} else {
+			granted++
+		}
+	}
+
+	if !cfg.Prune {
+		return
+	}
+	me, err := c.CurrentUser()
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+	keep := []string{owner, me}
+	users, err := c.ListCollaborators(nwo)
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+	teams, err := c.ListTeams(nwo)
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+	removeUsers, removeTeams := accessPrunePlan(owner, cfg, users, teams, keep)
+	for _, u := range removeUsers {
+		if err := c.RemoveUser(nwo, u); err != nil {
+			errs = append(errs, err)
+		} else {
+			removed++
+		}
+	}
+	for _, t := range removeTeams {
+		org, slug, _ := strings.Cut(t, "/")
+		if err := c.RemoveTeam(nwo, org, slug); err != nil {
+			errs = append(errs, err)
+		} else {
+			removed++
+		}
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestAccessPrunePlan(t *testing.T) {
+	cfg := config.AccessConfig{
+		Prune: true,
+		Teams: []config.TeamAccess{
+			{Team: "@acme/platform", Permission: "maintain"},
+			{Team: "security", Permission: "triage"},
+		},
+		Users: []config.UserAccess{{User: "Octocat", Permission: "push"}},
+	}
+	users := []string{"octocat", "acme-bot", "me", "stranger"}
+	teams := []string{"acme/platform", "acme/security", "acme/contractors"}
+
+	removeUsers, removeTeams := accessPrunePlan("acme", cfg, users, teams, []string{"acme", "me"})
+	if !reflect.DeepEqual(removeUsers, []string{"acme-bot", "stranger"}) {
+		t.Errorf("removeUsers = %v", removeUsers)
+	}
+	if !reflect.DeepEqual(removeTeams, []string{"acme/contractors"}) {
+		t.Errorf("removeTeams = %v", removeTeams)
+	}
+}
+
+func TestAccessPrunePlan_NothingToRemove(t *testing.T) {
+	cfg := config.AccessConfig{Users: []config.UserAccess{{User: "octocat", Permission: "push"}}}
+	removeUsers, removeTeams := accessPrunePlan("acme", cfg, []string{"octocat"}, nil, nil)
+	if len(removeUsers) != 0 || len(removeTeams) != 0 {
+		t.Errorf("expected nothing to remove, got users=%v teams=%v", removeUsers, removeTeams)
+	}
+}
@@ -0,0 +1,211 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	"github.com/ggfevans/gh-mint/internal/secrets"
+)
+
+// Secrets and variables live under a scope: the repo's Actions endpoints,
+// or a deployment environment. Both expose the same sub-paths.
+func actionsScope(nwo string) string {
+	return fmt.Sprintf("repos/%s/actions", nwo)
+}
+
+type secretsPublicKey struct {
+	KeyID string `json:"key_id"`
+	Key   string `json:"key"`
+}
+
+func (c *Client) secretsPublicKey(scope string) (secretsPublicKey, error) {
+	var key secretsPublicKey
+	out, err := c.api("GET", scope+"/secrets/public-key", nil)
+	if err != nil {
+		return key, fmt.Errorf("fetching secrets public key: %w", err)
+	}
+	if err := json.Unmarshal([]byte(out), &key); err != nil {
+		return key, fmt.Errorf("parsing secrets public key: %w", err)
+	}
+	return key, nil
+}
+
+func secretPayload(key secretsPublicKey, value string) (map[string]string, error) {
+	encrypted, err := secrets.SealBase64(key.Key, value)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"encrypted_value": encrypted, "key_id": key.KeyID}, nil
+}
+
+// syncSecrets resolves, encrypts, and uploads each secret in the scope.
+// Returns the number of secrets set.
+func (c *Client) syncSecrets(scope string, srcs map[string]config.SecretSource) (set int, errs []error) {
+	if len(srcs) == 0 {
+		return
+	}
+	key, err := c.secretsPublicKey(scope)
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(srcs)) {
+		value, err := secrets.Resolve(srcs[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("secret %s: %w", name, err))
+			continue
+		}
+		body, err := secretPayload(key, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("secret %s: %w", name, err))
+			continue
+		}
+		if _, err := c.api("PUT", scope+"/secrets/"+name, body); err != nil {
+			errs = append(errs, fmt.Errorf("setting secret %s: %w", name, err))
+			continue
+		}
+		set++
+	}
+	return
+}
+
+// syncVariables creates or updates each variable in the scope. Returns the
+// number of variables set.
+func (c *Client) syncVariables(scope string, vars map[string]string) (set int, errs []error) {
+	if len(vars) == 0 {
+		return
+	}
+	names, err := c.apiList(scope+"/variables", ".variables[].name")
+	if err != nil {
+		errs = append(errs, fmt.Errorf("listing variables: %w", err))
+		return
+	}
+	existing := make(map[string]bool, len(names))
+	for _, n := range names {
+		existing[n] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		body := map[string]string{"name": name, "value": vars[name]}
+		if existing[name] {
+			_, err = c.api("PATCH", scope+"/variables/"+name, body)
+		} else {
+			_, err = c.api("POST", scope+"/variables", body)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("setting variable %s: %w", name, err))
+			continue
+		}
+		set++
+	}
+	return
+}
+
+// SyncActionsVariables sets the repo's Actions variables.
+func (c *Client) SyncActionsVariables(nwo string, vars map[string]string) (int, []error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return 0, []error{fmt.Errorf("invalid nwo: %w", err)}
+	}
+	return c.syncVariables(actionsScope(nwo), vars)
+}
+
+// SyncActionsSecrets encrypts secrets locally with the repo's public key and
+// uploads them.
+func (c *Client) SyncActionsSecrets(nwo string, srcs map[string]config.SecretSource) (int, []error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return 0, []error{fmt.Errorf("invalid nwo: %w", err)}
+	}
+	return c.syncSecrets(actionsScope(nwo), srcs)
+}
+
+// actionsPolicyRequest is one Actions permissions call.
+type actionsPolicyRequest struct {
+	name     string
+	endpoint string
+	body     map[string]interface{}
+}
+
+// actionsPolicyRequests builds the permissions calls for the configured
+// policy, in order: the repo-level switch must come before the allowlist.
+func actionsPolicyRequests(nwo string, a config.ActionsConfig) []actionsPolicyRequest {
+	base := actionsScope(nwo) + "/permissions"
+	var reqs []actionsPolicyRequest
+
+	if a.Enabled != nil || a.AllowedActions != "" {
+		body := map[string]interface{}{"enabled": a.Enabled == nil || *a.Enabled}
+		if a.AllowedActions != "" {
+			body["allowed_actions"] = a.AllowedActions
+		}
+		reqs = append(reqs, actionsPolicyRequest{"Set Actions permissions", base, body})
+	}
+
+	if a.AllowedActions == "selected" && a.HasSelectedActions() {
+		body := map[string]interface{}{}
+		if a.GithubOwnedAllowed != nil {
+			body["github_owned_allowed"] = *a.GithubOwnedAllowed
+		}
+		if a.VerifiedAllowed != nil {
+			body["verified_allowed"] = *a.VerifiedAllowed
+		}
+		if a.PatternsAllowed != nil {
+			body["patterns_allowed"] = a.PatternsAllowed
+		}
+		reqs = append(reqs, actionsPolicyRequest{"Set allowed actions", base + "/selected-actions", body})
+	}
+
+	if a.HasWorkflowPermissions() {
+		body := map[string]interface{}{}
+		if a.DefaultWorkflowPermissions != "" {
+			body["default_workflow_permissions"] = a.DefaultWorkflowPermissions
+		}
+		if a.CanApprovePullRequestReviews != nil {
+			body["can_approve_pull_request_reviews"] = *a.CanApprovePullRequestReviews
+		}
+		reqs = append(reqs, actionsPolicyRequest{"Set workflow token permissions", base + "/workflow", body})
+	}
+	return reqs
+}
+
+// ApplyActionsPolicy applies the Actions permissions policy, one reported
+// step per endpoint. Returns the errors of failed steps.
+func (c *Client) ApplyActionsPolicy(nwo string, a config.ActionsConfig, report func(name string, err error)) []error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		err = fmt.Errorf("invalid nwo: %w", err)
+		report("Set Actions permissions", err)
+		return []error{err}
+	}
+	var errs []error
+	for _, r := range actionsPolicyRequests(nwo, a) {
+		_, err := c.api("PUT", r.endpoint, r.body)
+		if err != nil {
+			err = fmt.Errorf("setting actions policy: %w", err)
+			errs = append(errs, err)
+		}
+		report(r.name, err)
+	}
+	return errs
+}
+
+// EnableWorkflow enables a workflow by file name. Workflows pushed to a
+// fork start out disabled. GitHub registers a pushed workflow
+// asynchronously, so a workflow it doesn't know yet is waited for.
+func (c *Client) EnableWorkflow(nwo, file string) error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return fmt.Errorf("invalid nwo: %w", err)
+	}
+	endpoint := fmt.Sprintf("%s/workflows/%s/enable", actionsScope(nwo), file)
+	for i := 1; ; i++ {
+		_, err := c.api("PUT", endpoint, nil)
+		if err == nil {
+			return nil
+		}
+		if !strings.Contains(err.Error(), "HTTP 404") || i >= copyPoll.attempts {
+			return fmt.Errorf("enabling workflow %s: %w", file, err)
+		}
+		time.Sleep(copyPoll.interval)
+	}
+}
@@ -0,0 +1,83 @@
+package github
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestSecretPayload(t *testing.T) {
+	pub, priv, err := box.GenerateKey(bytes.NewReader(bytes.Repeat([]byte{1}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := secretsPublicKey{KeyID: "568250167242549743", Key: base64.StdEncoding.EncodeToString(pub[:])}
+	body, err := secretPayload(key, "registry-token")
+	if err != nil {
+		t.Fatalf("secretPayload: %v", err)
+	}
+	if body["key_id"] != key.KeyID {
+		t.Errorf("key_id = %q, want %q", body["key_id"], key.KeyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(body["encrypted_value"])
+	if err != nil {
+		t.Fatalf("encrypted_value is not base64: %v", err)
+	}
+	opened, ok := box.OpenAnonymous(nil, sealed, pub, priv)
+	if !ok || string(opened) != "registry-token" {
+		t.Errorf("decrypt failed: ok=%v opened=%q", ok, opened)
+	}
+}
+
+func TestActionsScope(t *testing.T) {
+	if got := actionsScope("owner/repo"); got != "repos/owner/repo/actions" {
+		t.Errorf("actionsScope = %q", got)
+	}
+}
+
+func TestActionsPolicyRequests_Hardened(t *testing.T) {
+	tr, f := true, false
+	a := config.ActionsConfig{
+		Enabled:                      &tr,
+		AllowedActions:               "selected",
+		GithubOwnedAllowed:           &tr,
+		PatternsAllowed:              []string{"docker/*", "acme/*@v1"},
+		DefaultWorkflowPermissions:   "read",
+		CanApprovePullRequestReviews: &f,
+	}
+	reqs := actionsPolicyRequests("owner/repo", a)
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(reqs))
+	}
+	if reqs[0].endpoint != "repos/owner/repo/actions/permissions" || reqs[0].body["allowed_actions"] != "selected" {
+		t.Errorf("permissions request = %+v", reqs[0])
+	}
+	if reqs[1].endpoint != "repos/owner/repo/actions/permissions/selected-actions" {
+		t.Errorf("selected-actions endpoint = %q", reqs[1].endpoint)
+	}
+	if _, ok := reqs[1].body["verified_allowed"]; ok {
+		t.Error("verified_allowed should be omitted when unset")
+	}
+	if reqs[2].body["default_workflow_permissions"] != "read" || reqs[2].body["can_approve_pull_request_reviews"] != false {
+		t.Errorf("workflow request body = %v", reqs[2].body)
+	}
+}
+
+func TestActionsPolicyRequests_AllowedActionsImpliesEnabled(t *testing.T) {
+	reqs := actionsPolicyRequests("owner/repo", config.ActionsConfig{AllowedActions: "local_only"})
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if reqs[0].body["enabled"] != true {
+		t.Errorf("enabled = %v, want true", reqs[0].body["enabled"])
+	}
+}
+
+func TestActionsPolicyRequests_Empty(t *testing.T) {
+	if reqs := actionsPolicyRequests("owner/repo", config.ActionsConfig{}); len(reqs) != 0 {
+		t.Errorf("expected no requests, got %+v", reqs)
+	}
+}
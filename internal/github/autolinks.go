@@ -0,0 +1,100 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+type existingAutolink struct {
+	ID             int64  `json:"id"`
+	KeyPrefix      string `json:"key_prefix"`
+	URLTemplate    string `json:"url_template"`
+	IsAlphanumeric bool   `json:"is_alphanumeric"`
+}
+
+// autolinkReplacement is a changed autolink: the old one is deleted and
+// the desired one created in its place.
+type autolinkReplacement struct {
+	oldID int64
+	link  config.Autolink
+}
+
+// autolinkPlan diffs existing autolinks against the profile by key prefix
+// (case-insensitive, as GitHub compares them). Autolinks can't be edited, so
+// a changed one is replaced. Unlisted autolinks are kept.
+func autolinkPlan(existing []existingAutolink, desired []config.Autolink) (create []config.Autolink, replace []autolinkReplacement) {
+	byPrefix := make(map[string]existingAutolink, len(existing))
+	for _, e := range existing {
+		byPrefix[strings.ToLower(e.KeyPrefix)] = e
+	}
+	for _, d := range desired {
+		e, ok := byPrefix[strings.ToLower(d.KeyPrefix)]
+		switch {
+		case !ok:
+			create = append(create, d)
+		case e.KeyPrefix != d.KeyPrefix || e.URLTemplate != d.URLTemplate || e.IsAlphanumeric != d.IsAlphanumeric():
+			replace = append(replace, autolinkReplacement{oldID: e.ID, link: d})
+		}
+	}
+	return
+}
+
+func (c *Client) listAutolinks(nwo string) ([]existingAutolink, error) {
+	out, err := c.api("GET", fmt.Sprintf("repos/%s/autolinks", nwo), nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing autolinks: %w", err)
+	}
+	var links []existingAutolink
+	if err := json.Unmarshal([]byte(out), &links); err != nil {
+		return nil, fmt.Errorf("parsing autolinks: %w", err)
+	}
+	return links, nil
+}
+
+func (c *Client) createAutolink(nwo string, l config.Autolink) error {
+	body := map[string]interface{}{
+		"key_prefix":      l.KeyPrefix,
+		"url_template":    l.URLTemplate,
+		"is_alphanumeric": l.IsAlphanumeric(),
+	}
+	if _, err := c.api("POST", fmt.Sprintf("repos/%s/autolinks", nwo), body); err != nil {
+		return fmt.Errorf("creating autolink %q: %w", l.KeyPrefix, err)
+	}
+	return nil
+}
+
+// SyncAutolinks creates missing autolinks and replaces changed ones.
+func (c *Client) SyncAutolinks(nwo string, links []config.Autolink) (created int, replaced int, errs []error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		errs = append(errs, fmt.Errorf("invalid nwo: %w", err))
+		return
+	}
+	existing, err := c.listAutolinks(nwo)
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+	create, replace := autolinkPlan(existing, links)
+	for _, r := range replace {
+		if _, err := c.api("DELETE", fmt.Sprintf("repos/%s/autolinks/%d", nwo, r.oldID), nil); err != nil {
+			errs = append(errs, fmt.Errorf("removing autolink %q: %w", r.link.KeyPrefix, err))
+			continue
+		}
+		if err := c.createAutolink(nwo, r.link); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		replaced++
+	}
+	for _, l := range create {
+		if err := c.createAutolink(nwo, l); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		created++
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestAutolinkPlan(t *testing.T) {
+	f := false
+	existing := []existingAutolink{
+		{ID: 1, KeyPrefix: "JIRA-", URLTemplate: "https://acme.atlassian.net/browse/JIRA-<num>", IsAlphanumeric: true},
+		{ID: 2, KeyPrefix: "INC-", URLTemplate: "https://old.example.com/<num>", IsAlphanumeric: true},
+		{ID: 3, KeyPrefix: "OTHER-", URLTemplate: "https://other.example.com/<num>", IsAlphanumeric: true},
+	}
+	desired := []config.Autolink{
+		{KeyPrefix: "JIRA-", URLTemplate: "https://acme.atlassian.net/browse/JIRA-<num>"},
+		{KeyPrefix: "INC-", URLTemplate: "https://incidents.example.com/<num>", Alphanumeric: &f},
+		{KeyPrefix: "OPS-", URLTemplate: "https://ops.example.com/<num>"},
+	}
+	create, replace := autolinkPlan(existing, desired)
+	if len(create) != 1 || create[0].KeyPrefix != "OPS-" {
+		t.Errorf("create = %+v, want OPS-", create)
+	}
+	if len(replace) != 1 || replace[0].oldID != 2 || replace[0].link.KeyPrefix != "INC-" {
+		t.Errorf("replace = %+v, want INC- replacing id 2", replace)
+	}
+}
+
+func TestSyncAutolinks_FakeEndpoint(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		switch r.Method {
+		case "GET":
+			return http.StatusOK, `[{"id": 9, "key_prefix": "INC-", "url_template": "https://old.example.com/<num>", "is_alphanumeric": true}]`
+		case "POST":
+			return http.StatusCreated, `{}`
+		case "DELETE":
+			return http.StatusNoContent, ""
+		}
+		return http.StatusNotFound, ""
+	})
+	links := []config.Autolink{
+		{KeyPrefix: "JIRA-", URLTemplate: "https://acme.atlassian.net/browse/JIRA-<num>"},
+		{KeyPrefix: "INC-", URLTemplate: "https://incidents.example.com/<num>"},
+	}
+	created, replaced, errs := c.SyncAutolinks("acme/widget", links)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if created != 1 || replaced != 1 {
+		t.Errorf("created/replaced = %d/%d, want 1/1", created, replaced)
+	}
+	reqs := api.recorded()
+	if len(reqs) != 4 || reqs[1].Method != "DELETE" || reqs[1].Path != "/repos/acme/widget/autolinks/9" {
+		t.Errorf("requests = %+v", reqs)
+	}
+}
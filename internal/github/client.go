@@ -2,34 +2,62 @@ package github
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
 // Client wraps the gh CLI for GitHub API interactions.
 // All commands use exec.Command with argument arrays — never shell interpolation.
 type Client struct {
-	ghPath string
+	ghPath    string
+	cacheDir  string       // empty disables on-disk caching
+	transport apiTransport // nil uses `gh api`
+
+	schemas map[string][]PropertySchema
+}
+
+// AuthError is returned when gh is missing, not logged in, or its token
+// lacks a scope a command needs.
+type AuthError struct {
+	Err error
 }
 
+func (e *AuthError) Error() string { return e.Err.Error() }
+func (e *AuthError) Unwrap() error { return e.Err }
+
 func NewClient() *Client {
-	return &Client{ghPath: "gh"}
+	c := &Client{ghPath: "gh"}
+	if dir, err := os.UserCacheDir(); err == nil {
+		c.cacheDir = filepath.Join(dir, "gh-mint")
+	}
+	return c
 }
 
 func (c *Client) CheckInstalled() error {
 	if _, err := exec.LookPath(c.ghPath); err != nil {
-		return fmt.Errorf("gh CLI not found. Install it from https://cli.github.com")
+		return &AuthError{fmt.Errorf("gh CLI not found. Install it from https://cli.github.com")}
 	}
 	out, err := c.run("auth", "status")
 	if err != nil {
-		return fmt.Errorf("gh auth failed: %s", out)
+		return &AuthError{fmt.Errorf("gh auth failed: %s", out)}
 	}
 	return nil
 }
 
 func (c *Client) run(args ...string) (string, error) {
+	return c.runInput(nil, args...)
+}
+
+// runInput is run with stdin connected to input when input is non-nil.
+func (c *Client) runInput(input []byte, args ...string) (string, error) {
 	cmd := exec.Command(c.ghPath, args...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -40,3 +68,85 @@ func (c *Client) run(args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// apiTransport performs a single REST call and returns the response body.
+// Tests swap it for an HTTP transport pointed at a local fake endpoint.
+type apiTransport func(method, endpoint string, body []byte) (string, error)
+
+func apiArgs(method, endpoint string, hasBody bool) []string {
+	args := []string{"api", endpoint, "-X", method}
+	if hasBody {
+		args = append(args, "--input", "-")
+	}
+	return args
+}
+
+// api calls `gh api` with the given method. A non-nil body is marshaled to
+// JSON and sent on stdin.
+func (c *Client) api(method, endpoint string, body interface{}) (string, error) {
+	var input []byte
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return "", fmt.Errorf("marshaling request body: %w", err)
+		}
+		input = data
+	}
+	if c.transport != nil {
+		return c.transport(method, endpoint, input)
+	}
+	return c.runInput(input, apiArgs(method, endpoint, input != nil)...)
+}
+
+// apiList fetches every page of a list endpoint and returns one line per
+// item, as selected by the jq filter.
+func (c *Client) apiList(endpoint, jq string) ([]string, error) {
+	out, err := c.run("api", "--paginate", endpoint, "--jq", jq)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// CurrentUser returns the login of the authenticated gh user.
+func (c *Client) CurrentUser() (string, error) {
+	out, err := c.run("api", "user", "--jq", ".login")
+	if err != nil {
+		return "", fmt.Errorf("getting current user: %w", err)
+	}
+	return out, nil
+}
+
+// graphql runs a GraphQL query or mutation and decodes the response's data
+// field into out, which may be nil.
+func (c *Client) graphql(query string, vars map[string]interface{}, out interface{}) error {
+	body := map[string]interface{}{"query": query}
+	if vars != nil {
+		body["variables"] = vars
+	}
+	resp, err := c.api("POST", "graphql", body)
+	if err != nil {
+		return err
+	}
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(resp), &envelope); err != nil {
+		return fmt.Errorf("parsing graphql response: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("graphql: %s", envelope.Errors[0].Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("parsing graphql data: %w", err)
+	}
+	return nil
+}
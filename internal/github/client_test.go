@@ -11,3 +11,19 @@ func TestCheckGHInstalled(t *testing.T) {
 		t.Skipf("gh not installed: %v", err)
 	}
 }
+
+func TestAPIArgs(t *testing.T) {
+	args := apiArgs("PUT", "repos/owner/repo/topics", true)
+	want := []string{"api", "repos/owner/repo/topics", "-X", "PUT", "--input", "-"}
+	if len(args) != len(want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Errorf("args[%d] = %q, want %q", i, args[i], want[i])
+		}
+	}
+	if got := apiArgs("DELETE", "repos/owner/repo", false); len(got) != 4 {
+		t.Errorf("expected no --input without a body: %v", got)
+	}
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	"github.com/ggfevans/gh-mint/internal/scaffold"
+)
+
+// cloneRepo clones nwo into dir, unless dir already holds a clone: the
+// boilerplate step or an earlier attempt may have made it.
+func (c *Client) cloneRepo(nwo, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return nil
+	}
+	if _, err := c.run("repo", "clone", nwo, dir); err != nil {
+		return fmt.Errorf("cloning repo: %w", err)
+	}
+	return nil
+}
+
+// localGitConfig returns the git config the profile sets in a clone, as
+// key-value pairs in the order they're applied.
+func localGitConfig(l config.LocalConfig) [][2]string {
+	var kv [][2]string
+	if l.UserEmail != "" {
+		kv = append(kv, [2]string{"user.email", l.UserEmail})
+	}
+	if l.SigningKey != "" {
+		if l.SSHSigning() {
+			kv = append(kv, [2]string{"gpg.format", "ssh"})
+		}
+		kv = append(kv, [2]string{"user.signingkey", l.SigningKey}, [2]string{"commit.gpgsign", "true"})
+	}
+	return kv
+}
+
+// configureClone applies the profile's git config, hooks, and extra
+// remotes to the clone in dir.
+func configureClone(dir string, l config.LocalConfig, vars config.RepoVars) error {
+	for _, kv := range localGitConfig(l) {
+		if _, err := runGit(dir, "config", kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	for _, hook := range slices.Sorted(maps.Keys(l.GitHooks)) {
+		if err := installGitHook(dir, hook, l.GitHooks[hook]); err != nil {
+			return err
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(l.Remotes)) {
+		url, err := config.RenderTemplate(l.Remotes[name], vars)
+		if err != nil {
+			return fmt.Errorf("remote %q: %w", name, err)
+		}
+		// A clone configured on an earlier attempt already has the remote.
+		verb := "add"
+		if _, err := runGit(dir, "remote", "get-url", name); err == nil {
+			verb = "set-url"
+		}
+		if _, err := runGit(dir, "remote", verb, name, url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installGitHook writes a hook from a template where git looks for it,
+// which honours core.hooksPath.
+func installGitHook(dir, hook, src string) error {
+	content, err := scaffold.ResolveTemplate(src, userTemplateDir())
+	if err != nil {
+		return fmt.Errorf("resolving git hook %q: %w", hook, err)
+	}
+	path, err := runGit(dir, "rev-parse", "--git-path", "hooks/"+hook)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0755); err != nil {
+		return fmt.Errorf("writing git hook %q: %w", hook, err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestLocalGitConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		local config.LocalConfig
+		want  [][2]string
+	}{
+		{"unset", config.LocalConfig{}, nil},
+		{"email", config.LocalConfig{UserEmail: "me@work.example"}, [][2]string{{"user.email", "me@work.example"}}},
+		{"gpg key", config.LocalConfig{SigningKey: "ABCD1234"}, [][2]string{
+			{"user.signingkey", "ABCD1234"}, {"commit.gpgsign", "true"},
+		}},
+		{"ssh key", config.LocalConfig{SigningKey: "~/.ssh/id_ed25519.pub"}, [][2]string{
+			{"gpg.format", "ssh"}, {"user.signingkey", "~/.ssh/id_ed25519.pub"}, {"commit.gpgsign", "true"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := localGitConfig(tt.local); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("localGitConfig = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigureClone(t *testing.T) {
+	dir := gitTestDir(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	hookDir := filepath.Join(home, ".config", "gh-mint", "templates")
+	if err := os.MkdirAll(hookDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hookDir, "pre-commit.sh"), []byte("#!/bin/sh\nexit 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGit(dir, "init"); err != nil {
+		t.Fatal(err)
+	}
+
+	local := config.LocalConfig{
+		UserEmail: "me@work.example",
+		GitHooks:  map[string]string{"pre-commit": "pre-commit.sh"},
+		Remotes:   map[string]string{"mirror": "git@gitlab.example:{{.NWO}}.git"},
+	}
+	if err := configureClone(dir, local, config.NewRepoVars("acme/widget")); err != nil {
+		t.Fatalf("configureClone: %v", err)
+	}
+
+	if got, err := runGit(dir, "config", "user.email"); err != nil || got != "me@work.example" {
+		t.Errorf("user.email = %q, %v", got, err)
+	}
+	if got, err := runGit(dir, "remote", "get-url", "mirror"); err != nil || got != "git@gitlab.example:acme/widget.git" {
+		t.Errorf("mirror remote = %q, %v", got, err)
+	}
+	if err := configureClone(dir, local, config.NewRepoVars("acme/widget")); err != nil {
+		t.Errorf("configureClone on a configured clone: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dir, ".git", "hooks", "pre-commit"))
+	if err != nil {
+		t.Fatalf("hook not installed: %v", err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("hook mode = %v, want executable", info.Mode())
+	}
+}
+
+func TestCreatePlan_Clone(t *testing.T) {
+	opts := CreateOpts{CloneDir: "/src/widget", Profile: config.Profile{
+		Boilerplate: config.BoilerplateConfig{Files: []config.BoilerplateFile{{Src: "a", Dest: "b"}}},
+	}}
+	plan := CreatePlan(opts)
+	last := plan[len(plan)-1]
+	if last.ID != "clone" || !reflect.DeepEqual(last.DependsOn, []string{"boilerplate"}) {
+		t.Errorf("last step = %+v, want clone after boilerplate", last)
+	}
+	for _, s := range CreatePlan(CreateOpts{}) {
+		if s.ID == "clone" {
+			t.Error("clone step without a clone dir")
+		}
+	}
+}
+
+func TestCommitAndPush_PushesEarlierCommit(t *testing.T) {
+	root := gitTestDir(t)
+	remote := filepath.Join(root, "remote.git")
+	if _, err := runGit(root, "init", "--bare", "--initial-branch", "main", remote); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "clone")
+	if _, err := runGit(root, "clone", remote, dir); err != nil {
+		t.Fatal(err)
+	}
+	if sha, err := commitAndPush(dir, "empty"); err != nil || sha != "" {
+		t.Errorf("nothing to commit: sha = %q, %v", sha, err)
+	}
+
+	// A commit whose push failed, as a kept clone has it on a resume.
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGit(dir, "add", "-A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGit(dir, "commit", "-m", "chore: add boilerplate files"); err != nil {
+		t.Fatal(err)
+	}
+	head, _ := runGit(dir, "rev-parse", "HEAD")
+
+	sha, err := commitAndPush(dir, "chore: add boilerplate files")
+	if err != nil || sha != head {
+		t.Fatalf("resume: sha = %q, %v; want %s pushed", sha, err, head)
+	}
+	if got, err := runGit(remote, "rev-parse", "main"); err != nil || got != head {
+		t.Errorf("remote main = %q, %v; want %s", got, err, head)
+	}
+	if sha, err := commitAndPush(dir, "chore: add boilerplate files"); err != nil || sha != "" {
+		t.Errorf("up to date: sha = %q, %v", sha, err)
+	}
+}
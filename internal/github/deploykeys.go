@@ -0,0 +1,119 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	"github.com/ggfevans/gh-mint/internal/secrets"
+)
+
+func deployKeyPayload(title, key string, readOnly bool) map[string]interface{} {
+	return map[string]interface{}{"title": title, "key": key, "read_only": readOnly}
+}
+
+// deployKeyMaterial returns the public key to upload for k. A generated
+// key's private half is written to its path before anything is uploaded,
+// so a failed write never leaves an unusable key on the repo, and the path
+// is returned so the key can be removed if the upload fails. When the key
+// has no path, the private half is returned for the caller to show once.
+func deployKeyMaterial(k config.DeployKey, title string, vars config.RepoVars) (publicKey string, privateKey []byte, keyPath string, err error) {
+	if !k.Generate {
+		publicKey, err = secrets.ReadPublicKey(k.PublicKeyFile)
+		return publicKey, nil, "", err
+	}
+	publicKey, privateKey, err = secrets.GenerateSSHKey(title, nil)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if k.PrivateKeyPath == "" {
+		return publicKey, privateKey, "", nil
+	}
+	path, err := config.RenderTemplate(k.PrivateKeyPath, vars)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if err := secrets.WritePrivateKey(path, privateKey); err != nil {
+		return "", nil, "", err
+	}
+	return publicKey, nil, path, nil
+}
+
+// pendingDeployKeys drops keys whose (unrendered) title is in done.
+func pendingDeployKeys(keys []config.DeployKey, done []string) []config.DeployKey {
+	skip := make(map[string]bool, len(done))
+	for _, t := range done {
+		skip[t] = true
+	}
+	var pending []config.DeployKey
+	for _, k := range keys {
+		if !skip[k.Title] {
+			pending = append(pending, k)
+		}
+	}
+	return pending
+}
+
+// privateKeyPaths returns where the generated keys among those with the
+// given (unrendered) titles wrote their private halves.
+func privateKeyPaths(keys []config.DeployKey, titles []string, vars config.RepoVars) []string {
+	added := make(map[string]bool, len(titles))
+	for _, t := range titles {
+		added[t] = true
+	}
+	var paths []string
+	for _, k := range keys {
+		if !added[k.Title] || !k.Generate || k.PrivateKeyPath == "" {
+			continue
+		}
+		if path, err := config.RenderTemplate(k.PrivateKeyPath, vars); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// AddDeployKeys uploads the profile's deploy keys and returns the profile
+// titles of those added. Generated keys without a private_key_path are
+// passed to showPrivateKey after upload; with no showPrivateKey they are
+// refused rather than lost.
+func (c *Client) AddDeployKeys(nwo string, keys []config.DeployKey, showPrivateKey func(title string, key []byte)) (added []string, errs []error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		errs = append(errs, fmt.Errorf("invalid nwo: %w", err))
+		return
+	}
+	vars := config.NewRepoVars(nwo)
+	for _, k := range keys {
+		title, err := config.RenderTemplate(k.Title, vars)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("deploy key %q: %w", k.Title, err))
+			continue
+		}
+		if k.Generate && k.PrivateKeyPath == "" && showPrivateKey == nil {
+			errs = append(errs, fmt.Errorf("deploy key %q: set private_key_path, the private key can't be shown here", title))
+			continue
+		}
+		publicKey, privateKey, keyPath, err := deployKeyMaterial(k, title, vars)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("deploy key %q: %w", title, err))
+			continue
+		}
+		body := deployKeyPayload(title, publicKey, k.IsReadOnly())
+		if _, err := c.api("POST", fmt.Sprintf("repos/%s/keys", nwo), body); err != nil {
+			// The key never reached the repo, so drop its private half
+			// and let a retry write a fresh one.
+			if keyPath != "" {
+				if rmErr := secrets.RemovePrivateKey(keyPath); rmErr != nil {
+					err = errors.Join(err, rmErr)
+				}
+			}
+			errs = append(errs, fmt.Errorf("adding deploy key %q: %w", title, err))
+			continue
+		}
+		added = append(added, k.Title)
+		if privateKey != nil {
+			showPrivateKey(title, privateKey)
+		}
+	}
+	return
+}
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	"github.com/ggfevans/gh-mint/internal/secrets"
+)
+
+func TestAddDeployKeys_FakeEndpoint(t *testing.T) {
+	dir := t.TempDir()
+	pub, _, err := secrets.GenerateSSHKey("existing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubFile := filepath.Join(dir, "existing.pub")
+	os.WriteFile(pubFile, []byte(pub+"\n"), 0644)
+
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		return http.StatusCreated, `{"id": 1}`
+	})
+
+	writable := false
+	keys := []config.DeployKey{
+		{Title: "mirror", PublicKeyFile: pubFile, ReadOnly: &writable},
+		{Title: "config-sync {{.Name}}", Generate: true, PrivateKeyPath: filepath.Join(dir, "{{.Name}}_sync")},
+		{Title: "ci", Generate: true},
+	}
+	var shown []string
+	added, errs := c.AddDeployKeys("acme/widget", keys, func(title string, key []byte) {
+		shown = append(shown, title)
+	})
+	if len(errs) > 0 || len(added) != 3 || added[1] != "config-sync {{.Name}}" {
+		t.Fatalf("AddDeployKeys = %v, %v", added, errs)
+	}
+	if len(shown) != 1 || shown[0] != "ci" {
+		t.Errorf("shown = %v, want only the key without a path", shown)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "widget_sync")); err != nil {
+		t.Errorf("private key not written: %v", err)
+	}
+
+	reqs := api.recorded()
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(reqs))
+	}
+	var first, second map[string]interface{}
+	json.Unmarshal([]byte(reqs[0].Body), &first)
+	json.Unmarshal([]byte(reqs[1].Body), &second)
+	if reqs[0].Path != "/repos/acme/widget/keys" || first["key"] != pub || first["read_only"] != false {
+		t.Errorf("first request = %+v", reqs[0])
+	}
+	if second["title"] != "config-sync widget" || second["read_only"] != true {
+		t.Errorf("second body = %v", second)
+	}
+	if !strings.HasPrefix(second["key"].(string), "ssh-ed25519 ") {
+		t.Errorf("generated key = %v", second["key"])
+	}
+}
+
+func TestAddDeployKeys_RefusesUnshowableKey(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		return http.StatusCreated, `{}`
+	})
+	added, errs := c.AddDeployKeys("acme/widget", []config.DeployKey{{Title: "ci", Generate: true}}, nil)
+	if len(added) != 0 || len(errs) != 1 {
+		t.Errorf("AddDeployKeys = %v, %v", added, errs)
+	}
+	if len(api.recorded()) != 0 {
+		t.Error("no key should be uploaded when its private half can't be shown")
+	}
+}
+
+func TestAddDeployKeys_RemovesKeyWhenUploadFails(t *testing.T) {
+	dir := t.TempDir()
+	fail := true
+	c, _ := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		if fail {
+			return http.StatusUnprocessableEntity, `{"message": "Validation Failed"}`
+		}
+		return http.StatusCreated, `{"id": 1}`
+	})
+	keys := []config.DeployKey{{Title: "sync", Generate: true, PrivateKeyPath: filepath.Join(dir, "sync")}}
+
+	if added, errs := c.AddDeployKeys("acme/widget", keys, nil); len(added) != 0 || len(errs) != 1 {
+		t.Fatalf("AddDeployKeys = %v, %v; want the upload to fail", added, errs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sync")); !os.IsNotExist(err) {
+		t.Errorf("private key left behind after a failed upload: %v", err)
+	}
+
+	fail = false
+	if added, errs := c.AddDeployKeys("acme/widget", keys, nil); len(added) != 1 || len(errs) != 0 {
+		t.Fatalf("retry: AddDeployKeys = %v, %v", added, errs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sync")); err != nil {
+		t.Errorf("private key not written on retry: %v", err)
+	}
+}
+
+func TestPrivateKeyPaths(t *testing.T) {
+	keys := []config.DeployKey{
+		{Title: "mirror", PublicKeyFile: "mirror.pub"},
+		{Title: "sync", Generate: true, PrivateKeyPath: "~/.ssh/{{.Name}}_sync"},
+		{Title: "ci", Generate: true},
+		{Title: "backup", Generate: true, PrivateKeyPath: "/keys/backup"},
+	}
+	got := privateKeyPaths(keys, []string{"mirror", "sync", "ci"}, config.NewRepoVars("acme/widget"))
+	if !reflect.DeepEqual(got, []string{"~/.ssh/widget_sync"}) {
+		t.Errorf("paths = %v, want only the added key with a path", got)
+	}
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// existingCategory is a discussion category as returned by GraphQL. The API
+// exposes whether a category is answerable but not the announcement format.
+type existingCategory struct {
+	Name         string `json:"name"`
+	Emoji        string `json:"emoji"`
+	Description  string `json:"description"`
+	IsAnswerable bool   `json:"isAnswerable"`
+}
+
+const discussionCategoriesQuery = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    discussionCategories(first: 100) { nodes { name emoji description isAnswerable } }
+  }
+}`
+
+// categoryDrift lists how a repo's discussion categories differ from the
+// profile, by category name.
+type categoryDrift struct {
+	missing, changed, extra []string
+}
+
+func (d categoryDrift) empty() bool {
+	return len(d.missing) == 0 && len(d.changed) == 0 && len(d.extra) == 0
+}
+
+func (d categoryDrift) String() string {
+	var parts []string
+	if len(d.missing) > 0 {
+		parts = append(parts, "missing: "+strings.Join(d.missing, ", "))
+	}
+	if len(d.changed) > 0 {
+		parts = append(parts, "changed: "+strings.Join(d.changed, ", "))
+	}
+	if len(d.extra) > 0 {
+		parts = append(parts, "not in profile: "+strings.Join(d.extra, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// discussionCategoryDrift compares categories by case-insensitive name.
+// Emoji and description are only compared when the profile sets them.
+func discussionCategoryDrift(existing []existingCategory, desired []config.DiscussionCategory) categoryDrift {
+	var drift categoryDrift
+	byName := make(map[string]existingCategory, len(existing))
+	for _, e := range existing {
+		byName[strings.ToLower(e.Name)] = e
+	}
+	want := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		key := strings.ToLower(d.Name)
+		want[key] = true
+		e, ok := byName[key]
+		switch {
+		case !ok:
+			drift.missing = append(drift.missing, d.Name)
+		case d.Emoji != "" && d.Emoji != e.Emoji,
+			d.Description != "" && d.Description != e.Description,
+			d.IsAnswerable() != e.IsAnswerable:
+			drift.changed = append(drift.changed, d.Name)
+		}
+	}
+	for _, e := range existing {
+		if !want[strings.ToLower(e.Name)] {
+			drift.extra = append(drift.extra, e.Name)
+		}
+	}
+	return drift
+}
+
+func (c *Client) listDiscussionCategories(nwo string) ([]existingCategory, error) {
+	vars := config.NewRepoVars(nwo)
+	var data struct {
+		Repository struct {
+			DiscussionCategories struct {
+				Nodes []existingCategory `json:"nodes"`
+			} `json:"discussionCategories"`
+		} `json:"repository"`
+	}
+	err := c.graphql(discussionCategoriesQuery, map[string]interface{}{"owner": vars.Owner, "name": vars.Name}, &data)
+	if err != nil {
+		return nil, fmt.Errorf("listing discussion categories: %w", err)
+	}
+	return data.Repository.DiscussionCategories.Nodes, nil
+}
+
+// CheckDiscussionCategories compares the repo's discussion categories with
+// the profile. GitHub's API can read categories but not create or edit
+// them, so any difference is returned as a note for the user to act on,
+// with a link to the settings page, rather than as an error. Only failing
+// to read the categories is an error.
+func (c *Client) CheckDiscussionCategories(nwo string, desired []config.DiscussionCategory) (string, error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return "", fmt.Errorf("invalid nwo: %w", err)
+	}
+	existing, err := c.listDiscussionCategories(nwo)
+	if err != nil {
+		return "", err
+	}
+	drift := discussionCategoryDrift(existing, desired)
+	if drift.empty() {
+		return "", nil
+	}
+	return fmt.Sprintf("Differs from profile (%s); the API cannot edit categories, update them at https://github.com/%s/discussions/categories", drift, nwo), nil
+}
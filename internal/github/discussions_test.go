@@ -0,0 +1,72 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestDiscussionCategoryDrift(t *testing.T) {
+	existing := []existingCategory{
+		{Name: "General", Emoji: ":speech_balloon:"},
+		{Name: "Q&A", Emoji: ":pray:", IsAnswerable: true},
+		{Name: "Announcements", Emoji: ":mega:", Description: "Updates"},
+	}
+	desired := []config.DiscussionCategory{
+		{Name: "q&a", Format: "qa"},
+		{Name: "Announcements", Emoji: ":loudspeaker:", Format: "announcement"},
+		{Name: "RFCs", Format: "open"},
+	}
+	drift := discussionCategoryDrift(existing, desired)
+	want := categoryDrift{
+		missing: []string{"RFCs"},
+		changed: []string{"Announcements"},
+		extra:   []string{"General"},
+	}
+	if !reflect.DeepEqual(drift, want) {
+		t.Errorf("drift = %+v, want %+v", drift, want)
+	}
+	if got := drift.String(); got != "missing: RFCs; changed: Announcements; not in profile: General" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if d := discussionCategoryDrift(existing[1:2], desired[:1]); !d.empty() {
+		t.Errorf("expected no drift, got %+v", d)
+	}
+}
+
+func TestCheckDiscussionCategories_FakeEndpoint(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		return http.StatusOK, `{"data": {"repository": {"discussionCategories": {"nodes": [
+			{"name": "General", "emoji": ":speech_balloon:", "description": "", "isAnswerable": false}
+		]}}}}`
+	})
+
+	note, err := c.CheckDiscussionCategories("acme/widget", []config.DiscussionCategory{{Name: "General"}})
+	if err != nil || note != "" {
+		t.Fatalf("matching categories: note = %q, err = %v", note, err)
+	}
+	note, err = c.CheckDiscussionCategories("acme/widget", []config.DiscussionCategory{{Name: "Ideas"}})
+	if err != nil {
+		t.Fatalf("drift is a note, not an error: %v", err)
+	}
+	if !strings.Contains(note, "missing: Ideas") || !strings.Contains(note, "not in profile: General") ||
+		!strings.Contains(note, "https://github.com/acme/widget/discussions/categories") {
+		t.Errorf("note = %q, want the drift and the settings link", note)
+	}
+
+	reqs := api.recorded()
+	var body struct {
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.Unmarshal([]byte(reqs[0].Body), &body); err != nil {
+		t.Fatalf("parsing request body: %v", err)
+	}
+	if reqs[0].Path != "/graphql" || body.Variables["owner"] != "acme" || body.Variables["name"] != "widget" {
+		t.Errorf("request = %+v", reqs[0])
+	}
+}
@@ -0,0 +1,184 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func environmentScope(nwo, name string) string {
+	return fmt.Sprintf("repos/%s/environments/%s", nwo, url.PathEscape(name))
+}
+
+// environmentPayload builds the environment PUT body. reviewerIDs holds the
+// resolved {type, id} for each reviewer, in order.
+func environmentPayload(e config.Environment, reviewerIDs []map[string]interface{}) map[string]interface{} {
+	if reviewerIDs == nil {
+		reviewerIDs = []map[string]interface{}{}
+	}
+	payload := map[string]interface{}{
+		"wait_timer":               e.WaitTimer,
+		"reviewers":                reviewerIDs,
+		"deployment_branch_policy": nil,
+	}
+	switch e.DeploymentBranches {
+	case "protected":
+		payload["deployment_branch_policy"] = map[string]bool{
+			"protected_branches":     true,
+			"custom_branch_policies": false,
+		}
+	case "custom":
+		payload["deployment_branch_policy"] = map[string]bool{
+			"protected_branches":     false,
+			"custom_branch_policies": true,
+		}
+	}
+	return payload
+}
+
+// branchPolicyPlan diffs existing deployment branch policies (name to id)
+// against the desired patterns.
+func branchPolicyPlan(existing map[string]int64, desired []string) (create []string, remove []int64) {
+	want := make(map[string]bool, len(desired))
+	for _, name := range desired {
+		want[name] = true
+		if _, ok := existing[name]; !ok {
+			create = append(create, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(existing)) {
+		if !want[name] {
+			remove = append(remove, existing[name])
+		}
+	}
+	return
+}
+
+func (c *Client) lookupID(endpoint string) (int64, error) {
+	out, err := c.api("GET", endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	var resp struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", endpoint, err)
+	}
+	return resp.ID, nil
+}
+
+// resolveReviewers looks up the numeric IDs the environments API requires.
+func (c *Client) resolveReviewers(owner string, reviewers []config.Reviewer) ([]map[string]interface{}, error) {
+	var ids []map[string]interface{}
+	for _, r := range reviewers {
+		if r.Team != "" {
+			org, slug := config.TeamAccess{Team: r.Team}.OrgSlug(owner)
+			id, err := c.lookupID(fmt.Sprintf("orgs/%s/teams/%s", org, slug))
+			if err != nil {
+				return nil, fmt.Errorf("looking up team %s/%s: %w", org, slug, err)
+			}
+			ids = append(ids, map[string]interface{}{"type": "Team", "id": id})
+			continue
+		}
+		id, err := c.lookupID("users/" + r.User)
+		if err != nil {
+			return nil, fmt.Errorf("looking up user %s: %w", r.User, err)
+		}
+		ids = append(ids, map[string]interface{}{"type": "User", "id": id})
+	}
+	return ids, nil
+}
+
+func (c *Client) listBranchPolicies(scope string) (map[string]int64, error) {
+	lines, err := c.apiList(scope+"/deployment-branch-policies", `.branch_policies[] | "\(.id) \(.name)"`)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployment branch policies: %w", err)
+	}
+	policies := make(map[string]int64, len(lines))
+	for _, line := range lines {
+		idStr, name, _ := strings.Cut(line, " ")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing deployment branch policy %q: %w", line, err)
+		}
+		policies[name] = id
+	}
+	return policies, nil
+}
+
+func (c *Client) syncBranchPolicies(scope string, desired []string) error {
+	existing, err := c.listBranchPolicies(scope)
+	if err != nil {
+		return err
+	}
+	create, remove := branchPolicyPlan(existing, desired)
+	for _, name := range create {
+		body := map[string]string{"name": name, "type": "branch"}
+		if _, err := c.api("POST", scope+"/deployment-branch-policies", body); err != nil {
+			return fmt.Errorf("creating deployment branch policy %q: %w", name, err)
+		}
+	}
+	for _, id := range remove {
+		if _, err := c.api("DELETE", fmt.Sprintf("%s/deployment-branch-policies/%d", scope, id), nil); err != nil {
+			return fmt.Errorf("removing deployment branch policy %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// SyncEnvironment creates or updates a deployment environment with its
+// protection rules, branch policies, variables, and secrets.
+func (c *Client) SyncEnvironment(nwo string, e config.Environment) []error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return []error{fmt.Errorf("invalid nwo: %w", err)}
+	}
+	scope := environmentScope(nwo, e.Name)
+
+	reviewers, err := c.resolveReviewers(config.NewRepoVars(nwo).Owner, e.Reviewers)
+	if err != nil {
+		return []error{err}
+	}
+	if _, err := c.api("PUT", scope, environmentPayload(e, reviewers)); err != nil {
+		return []error{fmt.Errorf("creating environment %s: %w", e.Name, err)}
+	}
+
+	var errs []error
+	if e.DeploymentBranches == "custom" {
+		if err := c.syncBranchPolicies(scope, e.BranchPolicies); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	_, varErrs := c.syncVariables(scope, e.Variables)
+	errs = append(errs, varErrs...)
+	_, secretErrs := c.syncSecrets(scope, e.Secrets)
+	errs = append(errs, secretErrs...)
+	return errs
+}
+
+// SyncEnvironments configures each environment as its own reported step.
+func (c *Client) SyncEnvironments(nwo string, envs []config.Environment, report func(name string, err error)) []error {
+	var errs []error
+	for _, e := range envs {
+		envErrs := c.SyncEnvironment(nwo, e)
+		var err error
+		switch len(envErrs) {
+		case 0:
+		case 1:
+			err = envErrs[0]
+		default:
+			err = fmt.Errorf("%d errors: %w", len(envErrs), envErrs[0])
+		}
+		report(fmt.Sprintf("Configured environment %s", e.Name), err)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
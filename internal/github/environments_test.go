@@ -0,0 +1,54 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestEnvironmentScope(t *testing.T) {
+	if got := environmentScope("owner/repo", "production"); got != "repos/owner/repo/environments/production" {
+		t.Errorf("environmentScope = %q", got)
+	}
+	if got := environmentScope("owner/repo", "pre prod"); got != "repos/owner/repo/environments/pre%20prod" {
+		t.Errorf("environmentScope should escape spaces, got %q", got)
+	}
+}
+
+func TestEnvironmentPayload(t *testing.T) {
+	e := config.Environment{Name: "production", WaitTimer: 10, DeploymentBranches: "custom"}
+	reviewers := []map[string]interface{}{{"type": "Team", "id": int64(42)}}
+	payload := environmentPayload(e, reviewers)
+	if payload["wait_timer"] != 10 {
+		t.Errorf("wait_timer = %v", payload["wait_timer"])
+	}
+	if !reflect.DeepEqual(payload["reviewers"], reviewers) {
+		t.Errorf("reviewers = %v", payload["reviewers"])
+	}
+	policy := payload["deployment_branch_policy"].(map[string]bool)
+	if !policy["custom_branch_policies"] || policy["protected_branches"] {
+		t.Errorf("deployment_branch_policy = %v", policy)
+	}
+}
+
+func TestEnvironmentPayload_AllBranches(t *testing.T) {
+	payload := environmentPayload(config.Environment{Name: "staging"}, nil)
+	if payload["deployment_branch_policy"] != nil {
+		t.Errorf("expected nil deployment_branch_policy, got %v", payload["deployment_branch_policy"])
+	}
+	if reviewers, ok := payload["reviewers"].([]map[string]interface{}); !ok || len(reviewers) != 0 {
+		t.Errorf("expected empty reviewers list, got %v", payload["reviewers"])
+	}
+}
+
+func TestBranchPolicyPlan(t *testing.T) {
+	existing := map[string]int64{"main": 1, "old/*": 2, "release/*": 3}
+	create, remove := branchPolicyPlan(existing, []string{"main", "release/*", "hotfix/*"})
+	if !reflect.DeepEqual(create, []string{"hotfix/*"}) {
+		t.Errorf("create = %v", create)
+	}
+	if !reflect.DeepEqual(remove, []int64{2}) {
+		t.Errorf("remove = %v", remove)
+	}
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordedRequest is a call received by the fake API.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// fakeAPI is a local HTTP endpoint standing in for the GitHub REST API.
+// It records every request and answers from handler.
+type fakeAPI struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+func (f *fakeAPI) recorded() []recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]recordedRequest(nil), f.requests...)
+}
+
+// newFakeAPIClient returns a Client whose REST calls go to a local server.
+// handler returns the status and response body for each request.
+func newFakeAPIClient(t *testing.T, handler func(r recordedRequest) (int, string)) (*Client, *fakeAPI) {
+	t.Helper()
+	f := &fakeAPI{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := recordedRequest{Method: r.Method, Path: r.URL.RequestURI(), Body: string(body)}
+		f.mu.Lock()
+		f.requests = append(f.requests, req)
+		f.mu.Unlock()
+		status, resp := handler(req)
+		w.WriteHeader(status)
+		io.WriteString(w, resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := &Client{ghPath: "gh"}
+	c.transport = func(method, endpoint string, body []byte) (string, error) {
+		req, err := http.NewRequest(method, srv.URL+"/"+endpoint, strings.NewReader(string(body)))
+		if err != nil {
+			return "", err
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode >= 300 {
+			return "", fmt.Errorf("%s %s: HTTP %d", method, endpoint, resp.StatusCode)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return c, f
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// forkPayload builds the request body for forking into org (empty for the
+// user's account) under name (empty to keep the upstream name).
+func forkPayload(org, name string) map[string]interface{} {
+	body := map[string]interface{}{"default_branch_only": false}
+	if org != "" {
+		body["organization"] = org
+	}
+	if name != "" {
+		body["name"] = name
+	}
+	return body
+}
+
+// ForkRepo forks upstream into org, or the user's account when org is
+// empty, optionally renamed. It returns the fork's owner/name and URL. If
+// the fork already exists GitHub returns it. Like generated repos, forks
+// are filled in asynchronously.
+func (c *Client) ForkRepo(upstream, org, name string) (nwo, url string, err error) {
+	if err := config.ValidateNWO(upstream); err != nil {
+		return "", "", fmt.Errorf("invalid upstream: %w", err)
+	}
+	out, err := c.api("POST", "repos/"+upstream+"/forks", forkPayload(org, name))
+	if err != nil {
+		return "", "", fmt.Errorf("forking %s: %w", upstream, err)
+	}
+	var fork struct {
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	}
+	if err := json.Unmarshal([]byte(out), &fork); err != nil {
+		return "", "", fmt.Errorf("parsing fork: %w", err)
+	}
+	return fork.FullName, fork.HTMLURL, nil
+}
+
+// ForkWithDefaults forks upstream into opts.Owner, renamed to opts.Name if
+// set, and applies the profile to the fork: the steps of ApplyProfile,
+// plus the upstream-sync workflow when the profile asks for it.
+func (c *Client) ForkWithDefaults(upstream string, opts CreateOpts) (string, error) {
+	// An owner that is the user's own login is a personal fork, which the
+	// API refuses as an organization.
+	org := opts.Owner
+	if org != "" {
+		if login, err := c.CurrentUser(); err == nil && strings.EqualFold(login, org) {
+			org = ""
+		}
+	}
+
+	start := time.Now()
+	nwo, url, err := c.ForkRepo(upstream, org, opts.Name)
+	name := "Forked " + upstream
+	if nwo != "" {
+		name = fmt.Sprintf("Forked %s to %s", upstream, nwo)
+	}
+	opts.report(name, err, time.Since(start))
+	if err != nil {
+		return "", err
+	}
+
+	run := &pipelineRun{c: c, opts: &opts, nwo: nwo}
+	errs, skipped := run.execute(profileSteps(&opts, modeFork), opts.Concurrency)
+	if len(errs) > 0 {
+		if skipped > 0 {
+			return url, fmt.Errorf("%d step(s) failed after forking, %d skipped", len(errs), skipped)
+		}
+		return url, fmt.Errorf("%d step(s) failed after forking", len(errs))
+	}
+	return url, nil
+}
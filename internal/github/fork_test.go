@@ -0,0 +1,120 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestForkPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		org, repo string
+		want      map[string]interface{}
+	}{
+		{"personal", "", "", map[string]interface{}{"default_branch_only": false}},
+		{"org", "acme", "", map[string]interface{}{"default_branch_only": false, "organization": "acme"}},
+		{"renamed", "acme", "widget-vendored", map[string]interface{}{"default_branch_only": false, "organization": "acme", "name": "widget-vendored"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := forkPayload(tt.org, tt.repo)
+			if len(got) != len(tt.want) {
+				t.Fatalf("payload = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestForkRepo(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		return http.StatusAccepted, `{"full_name": "acme/widget", "html_url": "https://github.com/acme/widget"}`
+	})
+	nwo, url, err := c.ForkRepo("upstream/widget", "acme", "")
+	if err != nil {
+		t.Fatalf("ForkRepo: %v", err)
+	}
+	if nwo != "acme/widget" || url != "https://github.com/acme/widget" {
+		t.Errorf("ForkRepo = %q, %q", nwo, url)
+	}
+	reqs := api.recorded()
+	if len(reqs) != 1 || reqs[0].Method != "POST" || reqs[0].Path != "/repos/upstream/widget/forks" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(reqs[0].Body), &body); err != nil || body["organization"] != "acme" {
+		t.Errorf("body = %s", reqs[0].Body)
+	}
+
+	if _, _, err := c.ForkRepo("not-a-repo", "", ""); err == nil {
+		t.Error("expected error for invalid upstream")
+	}
+}
+
+func TestForkPlan(t *testing.T) {
+	opts := CreateOpts{Profile: config.Profile{
+		Boilerplate: config.BoilerplateConfig{License: "mit"},
+		Seed:        config.SeedConfig{Issues: []config.SeedIssue{{Title: "Roadmap"}}},
+		Fork:        config.ForkConfig{SyncUpstream: true},
+	}}
+	var ids []string
+	for _, s := range ForkPlan(opts) {
+		ids = append(ids, s.ID)
+	}
+	want := []string{"settings", "contents", "security", "labels", "actions-policy", "boilerplate", "sync-workflow"}
+	if len(ids) != len(want) {
+		t.Fatalf("fork plan = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("fork plan = %v, want %v", ids, want)
+			break
+		}
+	}
+}
+
+func TestForkSteps_SyncUpstreamEnablesActions(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		return http.StatusNoContent, ""
+	})
+	opts := CreateOpts{Profile: config.Profile{Fork: config.ForkConfig{SyncUpstream: true}}}
+	for _, s := range profileSteps(&opts, modeFork) {
+		if s.id == "actions-policy" {
+			s.run(&stepRun{pipelineRun: &pipelineRun{c: c, opts: &opts, nwo: "me/widget"}})
+		}
+	}
+	reqs := api.recorded()
+	if len(reqs) != 1 || reqs[0].Path != "/repos/me/widget/actions/permissions" || !strings.Contains(reqs[0].Body, `"enabled":true`) {
+		t.Errorf("requests = %+v, want Actions enabled on the fork", reqs)
+	}
+}
+
+func TestEnableWorkflow_WaitsForRegistration(t *testing.T) {
+	saved := copyPoll
+	copyPoll.interval = time.Millisecond
+	t.Cleanup(func() { copyPoll = saved })
+
+	calls := 0
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		if calls++; calls == 1 {
+			return http.StatusNotFound, `{"message": "Not Found"}`
+		}
+		return http.StatusNoContent, ""
+	})
+	if err := c.EnableWorkflow("me/widget", "upstream-sync.yml"); err != nil {
+		t.Fatalf("EnableWorkflow: %v", err)
+	}
+	reqs := api.recorded()
+	if len(reqs) != 2 || reqs[1].Method != "PUT" || reqs[1].Path != "/repos/me/widget/actions/workflows/upstream-sync.yml/enable" {
+		t.Errorf("requests = %+v", reqs)
+	}
+}
@@ -0,0 +1,101 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// hookTimeout bounds each local command a profile runs. hookWaitDelay
+// bounds the wait for its output once it has exited or been killed, which
+// a background process it started could otherwise hold open forever.
+var (
+	hookTimeout   = 10 * time.Minute
+	hookWaitDelay = 5 * time.Second
+)
+
+// hookBaseEnv is all of gh-mint's environment a local command sees unless
+// the profile passes more through, so credentials such as GH_TOKEN stay
+// out of hooks by default.
+var hookBaseEnv = []string{"PATH", "HOME", "TMPDIR", "LANG"}
+
+// hookEnv describes the repo to a profile's local commands, followed by the
+// variables the profile passes through that are set.
+func hookEnv(hook, nwo string, pass []string) []string {
+	v := config.NewRepoVars(nwo)
+	env := []string{
+		"GH_MINT_HOOK=" + hook,
+		"GH_MINT_OWNER=" + v.Owner,
+		"GH_MINT_NAME=" + v.Name,
+		"GH_MINT_NWO=" + nwo,
+		"GH_MINT_URL=https://github.com/" + nwo,
+	}
+	return append(env, inheritEnv(pass)...)
+}
+
+// inheritEnv returns NAME=value for each of names set in gh-mint's
+// environment.
+func inheritEnv(names []string) []string {
+	var env []string
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// createHookEnv adds what create knows about the repo to hookEnv.
+func createHookEnv(hook, nwo string, opts *CreateOpts) []string {
+	visibility := "private"
+	if opts.Public {
+		visibility = "public"
+	}
+	return append(hookEnv(hook, nwo, opts.Profile.Hooks.PassEnv),
+		"GH_MINT_VISIBILITY="+visibility,
+		"GH_MINT_DESCRIPTION="+opts.Description,
+	)
+}
+
+// runLocalCommand runs argv in dir without a shell and no input, and returns
+// everything it printed. The command sees only hookBaseEnv from gh-mint's
+// environment, plus env.
+func runLocalCommand(dir string, argv, env []string) (string, error) {
+	if len(argv) == 0 {
+		return "", errors.New("empty command")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append(inheritEnv(hookBaseEnv), env...)
+	cmd.WaitDelay = hookWaitDelay
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		err = fmt.Errorf("timed out after %s", hookTimeout)
+	}
+	if err != nil {
+		return string(out), fmt.Errorf("%s: %w", strings.Join(argv, " "), err)
+	}
+	return string(out), nil
+}
+
+// runHooks runs a hook's commands in order in dir (the current directory
+// when empty), reporting each with its output. Commands often depend on the
+// ones before, so the first failure stops the rest.
+func runHooks(hook string, cmds [][]string, dir string, env []string, report func(name, output string, err error)) error {
+	for _, argv := range cmds {
+		out, err := runLocalCommand(dir, argv, env)
+		report(fmt.Sprintf("Ran %s: %s", hook, strings.Join(argv, " ")), out, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestRunLocalCommand(t *testing.T) {
+	dir := t.TempDir()
+	out, err := runLocalCommand(dir, []string{"go", "env", "GOFLAGS"}, []string{"GOFLAGS=-mod=mod"})
+	if err != nil || strings.TrimSpace(out) != "-mod=mod" {
+		t.Errorf("runLocalCommand = %q, %v; want the added environment", out, err)
+	}
+	// No shell: the argument is passed through as-is.
+	_, err = runLocalCommand(dir, []string{"go", "tool", "no-such-tool; true"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no-such-tool; true") {
+		t.Errorf("err = %v, want the failed command", err)
+	}
+	if _, err := runLocalCommand(dir, nil, nil); err == nil {
+		t.Error("expected error for an empty command")
+	}
+}
+
+func TestRunLocalCommand_Environment(t *testing.T) {
+	t.Setenv("GH_TOKEN", "secret")
+	t.Setenv("LANG", "C")
+	printEnv := []string{"sh", "-c", `echo "${GH_TOKEN-unset} $LANG $GH_MINT_HOOK"`}
+	out, err := runLocalCommand(t.TempDir(), printEnv, hookEnv("post_apply", "acme/widget", nil))
+	if err != nil || strings.TrimSpace(out) != "unset C post_apply" {
+		t.Errorf("runLocalCommand = %q, %v; want GH_TOKEN withheld", out, err)
+	}
+	out, err = runLocalCommand(t.TempDir(), printEnv, hookEnv("post_apply", "acme/widget", []string{"GH_TOKEN", "NOT_SET"}))
+	if err != nil || strings.TrimSpace(out) != "secret C post_apply" {
+		t.Errorf("runLocalCommand = %q, %v; want GH_TOKEN passed through", out, err)
+	}
+}
+
+func TestRunLocalCommand_Timeout(t *testing.T) {
+	old := hookTimeout
+	hookTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { hookTimeout = old })
+	_, err := runLocalCommand(t.TempDir(), []string{"sleep", "5"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+}
+
+func TestRunLocalCommand_TimeoutWithChildHoldingOutput(t *testing.T) {
+	oldTimeout, oldDelay := hookTimeout, hookWaitDelay
+	hookTimeout, hookWaitDelay = 50*time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() { hookTimeout, hookWaitDelay = oldTimeout, oldDelay })
+	// Killing sh leaves the background sleep holding the output pipe.
+	start := time.Now()
+	_, err := runLocalCommand(t.TempDir(), []string{"sh", "-c", "sleep 5 & sleep 5"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("took %s, want the wait for output bounded", elapsed)
+	}
+}
+
+func TestRunHooks_StopsAtFirstFailure(t *testing.T) {
+	var ran []string
+	var outputs []string
+	err := runHooks("post_create", [][]string{
+		{"go", "env", "GOFLAGS"},
+		{"go", "tool", "no-such-tool"},
+		{"go", "version"},
+	}, t.TempDir(), []string{"GOFLAGS=-mod=mod"}, func(name, output string, err error) {
+		ran = append(ran, name)
+		outputs = append(outputs, output)
+	})
+	if err == nil {
+		t.Fatal("expected the failing command's error")
+	}
+	want := []string{"Ran post_create: go env GOFLAGS", "Ran post_create: go tool no-such-tool"}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if strings.TrimSpace(outputs[0]) != "-mod=mod" || outputs[1] == "" {
+		t.Errorf("outputs = %q, want each command's output", outputs)
+	}
+}
+
+func TestCreateHookEnv(t *testing.T) {
+	got := createHookEnv("pre_create", "acme/widget", &CreateOpts{Public: true, Description: "A widget"})
+	want := []string{
+		"GH_MINT_HOOK=pre_create",
+		"GH_MINT_OWNER=acme",
+		"GH_MINT_NAME=widget",
+		"GH_MINT_NWO=acme/widget",
+		"GH_MINT_URL=https://github.com/acme/widget",
+		"GH_MINT_VISIBILITY=public",
+		"GH_MINT_DESCRIPTION=A widget",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("env = %v, want %v", got, want)
+	}
+}
+
+// TestScaffoldAndPush_Prepare pushes to a local bare repo through an
+// existing clone, which scaffoldAndPush uses instead of cloning.
+func TestScaffoldAndPush_Prepare(t *testing.T) {
+	base := gitTestDir(t)
+	t.Setenv("HOME", t.TempDir())
+	remote := filepath.Join(base, "remote.git")
+	clone := filepath.Join(base, "clone")
+	if _, err := runGit(base, "init", "--bare", "--initial-branch", "main", remote); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGit(base, "clone", remote, clone); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Client{}
+	prepare := func(dir string) error {
+		return os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/widget\n"), 0644)
+	}
+	sha, err := c.scaffoldAndPush("acme/widget", config.BoilerplateConfig{}, clone, prepare)
+	if err != nil {
+		t.Fatalf("scaffoldAndPush: %v", err)
+	}
+	pushed, err := runGit(remote, "rev-parse", "main")
+	if err != nil || sha == "" || pushed != sha {
+		t.Errorf("pushed %q, %v; want %q", pushed, err, sha)
+	}
+	if files, _ := runGit(remote, "show", "--name-only", "--format=", "main"); files != "go.mod" {
+		t.Errorf("pushed files = %q, want go.mod", files)
+	}
+
+	// With nothing new to commit, nothing is pushed.
+	sha, err = c.scaffoldAndPush("acme/widget", config.BoilerplateConfig{}, clone, prepare)
+	if err != nil || sha != "" {
+		t.Errorf("second scaffoldAndPush = %q, %v; want nothing pushed", sha, err)
+	}
+}
+
+func TestPlans_Hooks(t *testing.T) {
+	opts := CreateOpts{Profile: config.Profile{
+		BranchProtection: config.BranchProtection{Branch: "main"},
+		Hooks: config.HooksConfig{
+			PostCreate: [][]string{{"go", "mod", "init"}},
+			PostApply:  [][]string{{"catalog", "register"}},
+		},
+	}}
+
+	create := planIDs(CreatePlan(opts))
+	wantCreate := []string{"settings", "security", "labels", "actions-policy", "boilerplate", "branch-protection"}
+	if !reflect.DeepEqual(create, wantCreate) {
+		t.Errorf("create plan = %v, want %v", create, wantCreate)
+	}
+
+	apply := ApplyPlan(opts)
+	last := apply[len(apply)-1]
+	if last.ID != "post-apply" {
+		t.Fatalf("apply plan = %v, want post-apply last", planIDs(apply))
+	}
+	if want := planIDs(apply[:len(apply)-1]); !reflect.DeepEqual(last.DependsOn, want) {
+		t.Errorf("post-apply depends on %v, want %v", last.DependsOn, want)
+	}
+}
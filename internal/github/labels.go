@@ -59,7 +59,13 @@ func (c *Client) ListLabels(nwo string) ([]string, error) {
 	return names, nil
 }
 
-func (c *Client) SyncLabels(nwo string, cfg config.LabelConfig) (deleted int, created int, errs []error) {
+// LabelDiff lists the labels a sync deleted and created.
+type LabelDiff struct {
+	Deleted []string `json:"deleted"`
+	Created []string `json:"created"`
+}
+
+func (c *Client) SyncLabels(nwo string, cfg config.LabelConfig) (diff LabelDiff, errs []error) {
 	if cfg.ClearExisting {
 		existing, err := c.ListLabels(nwo)
 		if err != nil {
@@ -70,7 +76,7 @@ func (c *Client) SyncLabels(nwo string, cfg config.LabelConfig) (deleted int, cr
 			if err := c.DeleteLabel(nwo, name); err != nil {
 				errs = append(errs, err)
 			} else {
-				deleted++
+				diff.Deleted = append(diff.Deleted, name)
 			}
 		}
 	}
@@ -78,7 +84,56 @@ func (c *Client) SyncLabels(nwo string, cfg config.LabelConfig) (deleted int, cr
 		if err := c.CreateLabel(nwo, label); err != nil {
 			errs = append(errs, err)
 		} else {
-			created++
+			diff.Created = append(diff.Created, label.Name)
+		}
+	}
+	return
+}
+
+// labelReconcilePlan returns the existing labels to delete and the profile
+// labels to create. Only labels missing from the profile are deleted, and
+// only when clearing existing labels; names match case-insensitively.
+func labelReconcilePlan(existing []string, cfg config.LabelConfig) (remove []string, create []config.Label) {
+	want := make(map[string]bool, len(cfg.Items))
+	for _, l := range cfg.Items {
+		want[strings.ToLower(l.Name)] = true
+	}
+	have := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		have[strings.ToLower(name)] = true
+		if cfg.ClearExisting && !want[strings.ToLower(name)] {
+			remove = append(remove, name)
+		}
+	}
+	for _, l := range cfg.Items {
+		if !have[strings.ToLower(l.Name)] {
+			create = append(create, l)
+		}
+	}
+	return
+}
+
+// ReconcileLabels is SyncLabels for a repo that may already have some of
+// the profile's labels: they are kept rather than deleted and recreated.
+func (c *Client) ReconcileLabels(nwo string, cfg config.LabelConfig) (diff LabelDiff, errs []error) {
+	existing, err := c.ListLabels(nwo)
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+	remove, create := labelReconcilePlan(existing, cfg)
+	for _, name := range remove {
+		if err := c.DeleteLabel(nwo, name); err != nil {
+			errs = append(errs, err)
+		} else {
+			diff.Deleted = append(diff.Deleted, name)
+		}
+	}
+	for _, label := range create {
+		if err := c.CreateLabel(nwo, label); err != nil {
+			errs = append(errs, err)
+		} else {
+			diff.Created = append(diff.Created, label.Name)
 		}
 	}
 	return
@@ -46,3 +46,22 @@ func TestListLabelsArgs(t *testing.T) {
 		t.Errorf("missing label list: %v", args)
 	}
 }
+
+func TestLabelReconcilePlan(t *testing.T) {
+	cfg := config.LabelConfig{
+		ClearExisting: true,
+		Items:         []config.Label{{Name: "bug"}, {Name: "chore"}},
+	}
+	remove, create := labelReconcilePlan([]string{"Bug", "wontfix"}, cfg)
+	if len(remove) != 1 || remove[0] != "wontfix" {
+		t.Errorf("remove = %v, want [wontfix]", remove)
+	}
+	if len(create) != 1 || create[0].Name != "chore" {
+		t.Errorf("create = %v, want [chore]", create)
+	}
+
+	cfg.ClearExisting = false
+	if remove, _ := labelReconcilePlan([]string{"wontfix"}, cfg); len(remove) != 0 {
+		t.Errorf("remove = %v, want none without clear_existing", remove)
+	}
+}
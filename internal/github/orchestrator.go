@@ -1,23 +1,31 @@
 package github
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ggfevans/gh-mint/internal/config"
+	"github.com/ggfevans/gh-mint/internal/journal"
 	"github.com/ggfevans/gh-mint/internal/scaffold"
+	"github.com/ggfevans/gh-mint/internal/secrets"
 )
 
 // StepStatus represents the outcome of a single step.
 type StepStatus struct {
-	Name    string
-	Success bool
-	Message string
-	Err     error
+	Name     string
+	Success  bool
+	Message  string
+	Err      error
+	Skipped  bool          // not run because a step it depends on failed
+	Duration time.Duration // time the step took; zero for skipped steps
+	Output   string        // what a local command printed, or a note to act on
 }
 
 // ProgressFunc is called after each step completes.
@@ -29,8 +37,53 @@ type CreateOpts struct {
 	Description string
 	Public      bool
 	Profile     config.Profile
-	Owner       string // optional, for org repos
+	Owner       string   // optional, for org repos
+	Topics      []string // merged with the profile's topics
+	Homepage    string   // overrides the profile's homepage template
+	Template    string   // overrides the profile's template_repo
 	OnProgress  ProgressFunc
+
+	// OnPrivateKey shows a generated deploy key that has no
+	// private_key_path. Without it such keys are not created.
+	OnPrivateKey func(title string, key []byte)
+
+	// OnLabelDiff receives the labels the label step deleted and created.
+	OnLabelDiff func(LabelDiff)
+
+	// Journal, when set, records each step's outcome and lets a failed run
+	// be resumed. See CreateWithDefaults.
+	Journal *journal.Run
+
+	// RollbackOnFailure deletes the new repo if any step after creating it
+	// fails. See RollbackRepo for the safety checks. An adopted repo is
+	// never deleted.
+	RollbackOnFailure bool
+
+	// Adopt applies the profile to the repo if it already exists, running
+	// the steps of ApplyProfile instead of failing with RepoExistsError.
+	Adopt bool
+
+	// Concurrency caps how many independent steps run at once. Zero means
+	// the default of 4; 1 runs the steps one at a time in plan order.
+	Concurrency int
+
+	// CloneDir, when set, keeps a clone of the repo there and applies the
+	// profile's local settings to it. The directory must not exist yet.
+	CloneDir string
+}
+
+func (o *CreateOpts) homepage() string {
+	if o.Homepage != "" {
+		return o.Homepage
+	}
+	return o.Profile.Homepage
+}
+
+func (o *CreateOpts) template() string {
+	if o.Template != "" {
+		return o.Template
+	}
+	return o.Profile.TemplateRepo
 }
 
 func (o *CreateOpts) nwo() string {
@@ -40,11 +93,17 @@ func (o *CreateOpts) nwo() string {
 	return o.Name
 }
 
-func (o *CreateOpts) report(name string, err error) {
+func (o *CreateOpts) report(name string, err error, elapsed time.Duration) {
+	o.reportOutput(name, "", err, elapsed)
+}
+
+// reportOutput reports a step with output to show under it: what a local
+// command printed, or a note for the user.
+func (o *CreateOpts) reportOutput(name, output string, err error, elapsed time.Duration) {
 	if o.OnProgress == nil {
 		return
 	}
-	s := StepStatus{Name: name, Success: err == nil}
+	s := StepStatus{Name: name, Success: err == nil, Duration: elapsed, Output: output}
 	if err != nil {
 		s.Err = err
 		s.Message = err.Error()
@@ -52,115 +111,329 @@ func (o *CreateOpts) report(name string, err error) {
 	o.OnProgress(s)
 }
 
-// CreateWithDefaults creates a repo and applies all profile defaults.
-func (c *Client) CreateWithDefaults(opts CreateOpts) (string, error) {
-	repoArg := opts.Name
+// checkCustomProperties runs before the repo exists so that an org's
+// required custom properties are enforced on create. A schema that can't be
+// fetched is only an error when the profile sets properties; the owner may
+// be a user account rather than an organisation.
+func (c *Client) checkCustomProperties(opts CreateOpts) error {
+	props := opts.Profile.CustomProperties
+	if opts.Owner == "" {
+		if len(props) > 0 {
+			return fmt.Errorf("custom_properties require an organisation owner (set default_owner)")
+		}
+		return nil
+	}
+	schema, err := c.PropertySchema(opts.Owner)
+	if err != nil {
+		if len(props) > 0 {
+			return err
+		}
+		return nil
+	}
+	return validateCustomProperties(schema, props)
+}
+
+// targetNWO returns the owner/name the repo will be created as, looking up
+// the user's login when there is no owner.
+func (c *Client) targetNWO(opts CreateOpts) (string, error) {
 	if opts.Owner != "" {
-		repoArg = opts.Owner + "/" + opts.Name
+		return opts.nwo(), nil
 	}
-	url, err := c.CreateRepo(repoArg, opts.Description, opts.Public)
-	opts.report("Created repository", err)
+	login, err := c.CurrentUser()
 	if err != nil {
 		return "", err
 	}
+	return login + "/" + opts.Name, nil
+}
 
-	nwo := opts.nwo()
-	if url != "" && nwo == opts.Name {
-		parts := splitRepoURL(url)
-		if parts != "" {
-			nwo = parts
+// adopt records an existing repo as the run's target in place of creating
+// it.
+func (c *Client) adopt(opts CreateOpts, nwo string) string {
+	url := "https://github.com/" + nwo
+	opts.report("Adopted existing repository", nil, 0)
+	if j := opts.Journal; j != nil {
+		j.NWO, j.URL, j.Adopted = nwo, url, true
+		if jerr := j.Record("create", "Adopted existing repository", nil); jerr != nil {
+			opts.report("Saved run journal", jerr, 0)
 		}
 	}
+	return url
+}
 
-	var errs []error
+// CreateWithDefaults creates a repo and applies all profile defaults. With
+// a journal, each step's outcome is recorded as it finishes, and steps that
+// already succeeded in the journal are skipped, so a failed run can be
+// resumed by calling it again with the loaded journal.
+//
+// If the repo already exists, it fails with RepoExistsError, or with
+// opts.Adopt runs the steps of ApplyProfile against it instead.
+//
+// The journal is deleted when the run succeeds or is rolled back, and
+// otherwise marked finished so resume can tell it from an interrupted run.
+func (c *Client) CreateWithDefaults(opts CreateOpts) (string, error) {
+	url, err := c.createWithDefaults(opts)
+	if j := opts.Journal; j != nil {
+		if jerr := j.Finish(err == nil || j.Succeeded("rollback")); jerr != nil {
+			opts.report("Saved run journal", jerr, 0)
+		}
+	}
+	return url, err
+}
 
-	// Apply settings
-	settings, err := SettingsFromRepoSettings(opts.Profile.Settings)
-	if err != nil {
-		opts.report("Applied repo settings", err)
-		errs = append(errs, err)
+func (c *Client) createWithDefaults(opts CreateOpts) (string, error) {
+	var url, nwo string
+	var adopted bool
+	if j := opts.Journal; j != nil && j.Succeeded("create") {
+		url, nwo, adopted = j.URL, j.NWO, j.Adopted
 	} else {
-		err = c.UpdateSettings(nwo, settings)
-		opts.report("Applied repo settings", err)
+		if err := c.checkCustomProperties(opts); err != nil {
+			return "", err
+		}
+		target, err := c.targetNWO(opts)
 		if err != nil {
-			errs = append(errs, err)
+			return "", err
 		}
-	}
-
-	// Sync labels
-	deleted, created, labelErrs := c.SyncLabels(nwo, opts.Profile.Labels)
-	var labelErr error
-	if len(labelErrs) > 0 {
-		labelErr = fmt.Errorf("%d label errors", len(labelErrs))
-		errs = append(errs, labelErr)
-	}
-	opts.report(fmt.Sprintf("Synced labels (-%d/+%d)", deleted, created), labelErr)
-
-	// Scaffold boilerplate
-	if len(opts.Profile.Boilerplate.Files) > 0 {
-		err = c.scaffoldAndPush(nwo, opts.Profile.Boilerplate, opts.Name)
-		opts.report("Pushed boilerplate files", err)
+		exists, err := c.RepoExists(target)
 		if err != nil {
-			errs = append(errs, err)
+			return "", err
+		}
+		switch {
+		case exists && !opts.Adopt:
+			return "", &RepoExistsError{NWO: target}
+		case exists:
+			nwo, adopted = target, true
+			url = c.adopt(opts, nwo)
+		default:
+			if err := c.preCreate(opts, target, ""); err != nil {
+				return "", err
+			}
+			if url, nwo, err = c.createRepo(opts); err != nil {
+				return "", err
+			}
 		}
 	}
 
-	// Branch protection
-	if opts.Profile.BranchProtection.Branch != "" {
-		err = c.SetBranchProtection(nwo, opts.Profile.BranchProtection)
-		opts.report("Set branch protection", err)
-		if err != nil {
-			errs = append(errs, err)
-		}
+	mode := modeCreate
+	if adopted {
+		mode = modeApply
 	}
+	run := &pipelineRun{c: c, opts: &opts, nwo: nwo}
+	// Seeded issues from an earlier attempt, for the project step.
+	run.savedOutput("seed", &run.seed)
+	errs, skipped := run.execute(profileSteps(&opts, mode), opts.Concurrency)
 
 	if len(errs) > 0 {
-		return url, fmt.Errorf("%d step(s) failed after repo creation", len(errs))
+		after := "after repo creation"
+		if adopted {
+			after = "on adopted repo"
+		}
+		err := fmt.Errorf("%d step(s) failed %s", len(errs), after)
+		if skipped > 0 {
+			err = fmt.Errorf("%d step(s) failed %s, %d skipped", len(errs), after, skipped)
+		}
+		if !opts.RollbackOnFailure || adopted {
+			return url, err
+		}
+		if rbErr := c.rollback(run); rbErr != nil {
+			return url, fmt.Errorf("%w; rollback failed: %v", err, rbErr)
+		}
+		return "", fmt.Errorf("%w; repository %s was deleted", err, nwo)
 	}
 	return url, nil
 }
 
-func (c *Client) scaffoldAndPush(nwo string, bp config.BoilerplateConfig, repoName string) error {
-	tmpDir, err := os.MkdirTemp("", "gh-mint-*")
+// preCreate runs the profile's pre_create hooks in dir (the current
+// directory when empty). A failing hook stops the create before anything
+// is made.
+func (c *Client) preCreate(opts CreateOpts, nwo, dir string) error {
+	cmds := opts.Profile.Hooks.PreCreate
+	if len(cmds) == 0 {
+		return nil
+	}
+	start := time.Now()
+	err := runHooks("pre_create", cmds, dir, createHookEnv("pre_create", nwo, &opts), func(name, output string, err error) {
+		opts.reportOutput(name, output, err, time.Since(start))
+		start = time.Now()
+	})
 	if err != nil {
-		return fmt.Errorf("creating temp dir: %w", err)
+		return fmt.Errorf("pre_create hook: %w", err)
 	}
-	defer os.RemoveAll(tmpDir)
+	return nil
+}
 
-	cloneDir := filepath.Join(tmpDir, repoName)
-	if _, err := c.run("repo", "clone", nwo, cloneDir); err != nil {
-		return fmt.Errorf("cloning repo: %w", err)
+// createRepo creates the repo and records it in the journal.
+func (c *Client) createRepo(opts CreateOpts) (url, nwo string, err error) {
+	if opts.RollbackOnFailure {
+		if err := c.CheckDeleteScope(); err != nil {
+			return "", "", fmt.Errorf("--rollback-on-failure: %w", err)
+		}
 	}
 
-	var userTemplateDir string
-	if home, err := os.UserHomeDir(); err == nil {
-		userTemplateDir = filepath.Join(home, ".config", "gh-mint", "templates")
+	repoArg := opts.Name
+	if opts.Owner != "" {
+		repoArg = opts.Owner + "/" + opts.Name
+	}
+	start := time.Now()
+	name := "Created repository"
+	if tmpl := opts.template(); tmpl != "" {
+		name = "Created repository from " + tmpl
+		url, err = c.CreateRepoFromTemplate(repoArg, tmpl, opts.Description, opts.Public)
+	} else {
+		url, err = c.CreateRepo(repoArg, opts.Description, opts.Public)
 	}
+	opts.report(name, err, time.Since(start))
 
-	if _, err := scaffold.PrepareBoilerplate(bp, cloneDir, userTemplateDir); err != nil {
-		return fmt.Errorf("preparing boilerplate: %w", err)
+	nwo = opts.nwo()
+	if url != "" && nwo == opts.Name {
+		parts := splitRepoURL(url)
+		if parts != "" {
+			nwo = parts
+		}
 	}
+	if j := opts.Journal; j != nil {
+		j.NWO, j.URL = nwo, url
+		if jerr := j.Record("create", name, err); jerr != nil {
+			opts.report("Saved run journal", jerr, 0)
+		}
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return url, nwo, nil
+}
 
-	gitCmd := func(args ...string) error {
-		cmd := exec.Command("git", args...)
-		cmd.Dir = cloneDir
-		if out, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("git %v: %w\n%s", args, err, string(out))
+// ApplyProfile applies opts.Profile to an existing repo. It runs the same
+// step graph as CreateWithDefaults, minus the steps that only make sense on
+// a new repo: seeding, deploy keys, and boilerplate.
+func (c *Client) ApplyProfile(nwo string, opts CreateOpts) error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return fmt.Errorf("invalid nwo: %w", err)
+	}
+	run := &pipelineRun{c: c, opts: &opts, nwo: nwo}
+	errs, skipped := run.execute(profileSteps(&opts, modeApply), opts.Concurrency)
+	if len(errs) > 0 {
+		if skipped > 0 {
+			return fmt.Errorf("%d step(s) failed, %d skipped", len(errs), skipped)
 		}
-		return nil
+		return fmt.Errorf("%d step(s) failed", len(errs))
 	}
-	if err := gitCmd("add", "-A"); err != nil {
+	return nil
+}
+
+// rollback deletes the repo and reports each applied step it undid. The
+// private keys of the repo's deploy keys are useless once it's gone, so
+// they are removed too. A clone is left for the user to delete, since it
+// may hold their own work by now.
+func (c *Client) rollback(run *pipelineRun) error {
+	undone := run.applied
+	start := time.Now()
+	err := c.RollbackRepo(run.nwo, run.scaffoldSHA, run.templateSHA)
+	run.record("rollback", "Rolled back", err)
+	run.opts.report(fmt.Sprintf("Deleted repository %s", run.nwo), err, time.Since(start))
+	if err != nil {
 		return err
 	}
-	if err := gitCmd("commit", "-m", "chore: add boilerplate files"); err != nil {
-		return err
+	for _, name := range undone {
+		run.opts.report("Undid: "+name, nil, 0)
 	}
-	if err := gitCmd("push"); err != nil {
-		return err
+	for _, path := range run.keyFiles {
+		run.opts.report("Removed private key "+path, secrets.RemovePrivateKey(path), 0)
+	}
+	if dir := run.opts.CloneDir; dir != "" {
+		if _, err := os.Stat(dir); err == nil {
+			run.opts.report("Left behind: clone at "+dir, nil, 0)
+		}
 	}
 	return nil
 }
 
+// scaffoldAndPush commits the boilerplate to a clone and pushes it,
+// returning the commit SHA. The clone is made in cloneDir and kept, or in a
+// temp dir when cloneDir is empty. prepare, if set, runs in the clone before
+// the commit. If nothing changed and nothing is left unpushed from an
+// earlier attempt, nothing is pushed and the SHA is empty.
+func (c *Client) scaffoldAndPush(nwo string, bp config.BoilerplateConfig, cloneDir string, prepare func(dir string) error) (string, error) {
+	if cloneDir == "" {
+		tmpDir, err := os.MkdirTemp("", "gh-mint-*")
+		if err != nil {
+			return "", fmt.Errorf("creating temp dir: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+		cloneDir = filepath.Join(tmpDir, path.Base(nwo))
+	}
+	if err := c.cloneRepo(nwo, cloneDir); err != nil {
+		return "", err
+	}
+
+	if _, err := scaffold.PrepareBoilerplate(bp, cloneDir, userTemplateDir()); err != nil {
+		return "", fmt.Errorf("preparing boilerplate: %w", err)
+	}
+	if prepare != nil {
+		if err := prepare(cloneDir); err != nil {
+			return "", err
+		}
+	}
+
+	return commitAndPush(cloneDir, "chore: add boilerplate files")
+}
+
+// commitAndPush commits any changes in dir and pushes, returning the
+// pushed HEAD. A kept clone may hold a commit whose push failed on an
+// earlier attempt, so commits not on the upstream branch are pushed even
+// when there is nothing new to commit. If there is nothing to push the SHA
+// is empty.
+func commitAndPush(dir, message string) (string, error) {
+	status, err := runGit(dir, "status", "--porcelain")
+	if err != nil {
+		return "", err
+	}
+	if status != "" {
+		if _, err := runGit(dir, "add", "-A"); err != nil {
+			return "", err
+		}
+		if _, err := runGit(dir, "commit", "-m", message); err != nil {
+			return "", err
+		}
+	}
+	sha, err := runGit(dir, "rev-parse", "--verify", "--quiet", "HEAD")
+	if err != nil {
+		return "", nil // a clone of an empty repo, with nothing to commit
+	}
+	// The upstream branch doesn't exist until the first push to an empty
+	// repo, and then everything is ahead of it.
+	if ahead, err := runGit(dir, "rev-list", "--count", "@{upstream}..HEAD"); err == nil && ahead == "0" {
+		return "", nil
+	}
+	if _, err := runGit(dir, "push"); err != nil {
+		return "", err
+	}
+	return sha, nil
+}
+
+// userTemplateDir is where the user's own templates override the built-in
+// ones, or empty if there is no home directory.
+func userTemplateDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh-mint", "templates")
+}
+
+// runGit runs git in dir and returns its trimmed output. A failure
+// includes everything git printed.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %v: %w\n%s%s", args, err, stdout.String(), stderr.String())
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
 func splitRepoURL(url string) string {
 	nwo, ok := strings.CutPrefix(url, "https://github.com/")
 	if !ok || nwo == "" {
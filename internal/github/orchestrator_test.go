@@ -1,9 +1,13 @@
 package github
 
 import (
+	"errors"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/ggfevans/gh-mint/internal/config"
+	"github.com/ggfevans/gh-mint/internal/journal"
 )
 
 func TestSettingsFromRepoSettings(t *testing.T) {
@@ -63,3 +67,141 @@ func TestSplitRepoURL(t *testing.T) {
 		}
 	}
 }
+
+func TestSettingsFromRepoSettings_NewFields(t *testing.T) {
+	tr := true
+	s := config.RepoSettings{
+		AllowAutoMerge:           &tr,
+		AllowUpdateBranch:        &tr,
+		WebCommitSignoffRequired: &tr,
+		MergeCommitTitle:         "PR_TITLE",
+		MergeCommitMessage:       "PR_BODY",
+	}
+	m, err := SettingsFromRepoSettings(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{"allow_auto_merge", "allow_update_branch", "web_commit_signoff_required"} {
+		if m[key] != true {
+			t.Errorf("%s = %v, want true", key, m[key])
+		}
+	}
+	if m["merge_commit_title"] != "PR_TITLE" {
+		t.Errorf("merge_commit_title = %v, want PR_TITLE", m["merge_commit_title"])
+	}
+	if _, ok := m["has_issues"]; ok {
+		t.Error("has_issues should be omitted when nil")
+	}
+}
+
+func TestCreateWithDefaults_ResumesFromJournal(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		if r.Method == "GET" {
+			return http.StatusOK, `{"names": ["go"]}`
+		}
+		return http.StatusOK, `{}`
+	})
+
+	run, err := journal.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run.NWO, run.URL = "acme/widget", "https://github.com/acme/widget"
+	for _, id := range []string{"create", "settings", "security", "labels", "actions-policy"} {
+		run.Record(id, id, nil)
+	}
+	run.Record("topics", "Set topics", errors.New("HTTP 502"))
+
+	var reported []string
+	opts := CreateOpts{
+		Name:    "widget",
+		Owner:   "acme",
+		Profile: config.Profile{Topics: config.TopicConfig{Items: []string{"cli"}}},
+		Journal: run,
+		OnProgress: func(s StepStatus) {
+			reported = append(reported, s.Name)
+		},
+	}
+	url, err := c.CreateWithDefaults(opts)
+	if err != nil {
+		t.Fatalf("CreateWithDefaults: %v", err)
+	}
+	if url != "https://github.com/acme/widget" {
+		t.Errorf("url = %q", url)
+	}
+	if len(reported) != 1 || reported[0] != "Set topics (2)" {
+		t.Errorf("reported = %v, want only the retried topics step", reported)
+	}
+	if reqs := api.recorded(); len(reqs) != 2 {
+		t.Errorf("expected topics GET and PUT only, got %+v", reqs)
+	}
+	if !run.Succeeded("topics") || len(run.Failed()) != 0 {
+		t.Errorf("journal not updated: %+v", run.Failed())
+	}
+}
+
+func TestCreateWithDefaults_RepoExists(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		if r.Path == "/repos/acme/widget" {
+			return http.StatusOK, `{"full_name": "acme/widget"}`
+		}
+		return http.StatusNotFound, `{}`
+	})
+	_, err := c.CreateWithDefaults(CreateOpts{Name: "widget", Owner: "acme"})
+	var exists *RepoExistsError
+	if !errors.As(err, &exists) || exists.NWO != "acme/widget" {
+		t.Fatalf("err = %v, want RepoExistsError for acme/widget", err)
+	}
+	for _, r := range api.recorded() {
+		if r.Method != "GET" {
+			t.Errorf("unexpected write to existing repo: %s %s", r.Method, r.Path)
+		}
+	}
+}
+
+func TestCreateWithDefaults_AdoptsExistingRepo(t *testing.T) {
+	c, _ := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		switch r.Path {
+		case "/repos/acme/widget":
+			return http.StatusOK, `{"full_name": "acme/widget"}`
+		case "/repos/acme/widget/topics":
+			return http.StatusOK, `{"names": []}`
+		}
+		return http.StatusNotFound, `{}`
+	})
+
+	// Steps other than topics are marked done so the test stays offline.
+	run, err := journal.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"settings", "security", "labels", "actions-policy"} {
+		run.Record(id, id, nil)
+	}
+
+	var reported []string
+	url, err := c.CreateWithDefaults(CreateOpts{
+		Name:  "widget",
+		Owner: "acme",
+		Adopt: true,
+		Profile: config.Profile{
+			Topics: config.TopicConfig{Items: []string{"cli"}},
+			Seed:   config.SeedConfig{Issues: []config.SeedIssue{{Title: "Roadmap"}}},
+		},
+		Journal:    run,
+		OnProgress: func(s StepStatus) { reported = append(reported, s.Name) },
+	})
+	if err != nil {
+		t.Fatalf("CreateWithDefaults: %v", err)
+	}
+	if url != "https://github.com/acme/widget" {
+		t.Errorf("url = %q", url)
+	}
+	want := []string{"Adopted existing repository", "Set topics (1)"}
+	if strings.Join(reported, "|") != strings.Join(want, "|") {
+		t.Errorf("reported = %v, want %v (no seeding on an adopted repo)", reported, want)
+	}
+	if !run.Adopted || run.NWO != "acme/widget" || !run.Succeeded("create") {
+		t.Errorf("journal = %+v, want an adopted run for acme/widget", run)
+	}
+}
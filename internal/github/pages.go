@@ -0,0 +1,87 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// pagesPayload builds the body for enabling Pages. Legacy builds publish
+// from a branch, defaulting to the given default branch at the root.
+func pagesPayload(p config.PagesConfig, defaultBranch string) map[string]interface{} {
+	if p.IsWorkflow() {
+		return map[string]interface{}{"build_type": "workflow"}
+	}
+	branch := p.Branch
+	if branch == "" {
+		branch = defaultBranch
+	}
+	path := p.Path
+	if path == "" {
+		path = "/"
+	}
+	return map[string]interface{}{
+		"build_type": "legacy",
+		"source":     map[string]string{"branch": branch, "path": path},
+	}
+}
+
+// pagesUpdatePayload builds the body for the custom domain and HTTPS
+// settings, or nil when neither is configured.
+func pagesUpdatePayload(p config.PagesConfig, nwo string) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	if p.CNAME != "" {
+		cname, err := config.RenderTemplate(p.CNAME, config.NewRepoVars(nwo))
+		if err != nil {
+			return nil, err
+		}
+		body["cname"] = strings.ToLower(cname)
+	}
+	if p.HTTPSEnforced != nil {
+		body["https_enforced"] = *p.HTTPSEnforced
+	}
+	if len(body) == 0 {
+		return nil, nil
+	}
+	return body, nil
+}
+
+// ConfigurePages enables Pages if it isn't already, then applies the
+// custom domain and HTTPS settings.
+func (c *Client) ConfigurePages(nwo string, p config.PagesConfig) error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return fmt.Errorf("invalid nwo: %w", err)
+	}
+	var defaultBranch string
+	if !p.IsWorkflow() && p.Branch == "" {
+		branch, err := c.DefaultBranch(nwo)
+		if err != nil {
+			return err
+		}
+		defaultBranch = branch
+	}
+	endpoint := fmt.Sprintf("repos/%s/pages", nwo)
+	// Pages that were never enabled answer 404.
+	if _, err := c.api("GET", endpoint, nil); err != nil {
+		if !strings.Contains(err.Error(), "HTTP 404") {
+			return fmt.Errorf("checking pages: %w", err)
+		}
+		if _, err := c.api("POST", endpoint, pagesPayload(p, defaultBranch)); err != nil {
+			return fmt.Errorf("enabling pages: %w", err)
+		}
+	} else if _, err := c.api("PUT", endpoint, pagesPayload(p, defaultBranch)); err != nil {
+		return fmt.Errorf("updating pages build: %w", err)
+	}
+
+	body, err := pagesUpdatePayload(p, nwo)
+	if err != nil {
+		return fmt.Errorf("configuring pages: %w", err)
+	}
+	if body != nil {
+		if _, err := c.api("PUT", endpoint, body); err != nil {
+			return fmt.Errorf("configuring pages domain: %w", err)
+		}
+	}
+	return nil
+}
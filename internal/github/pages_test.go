@@ -0,0 +1,81 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestPagesPayload_Workflow(t *testing.T) {
+	body := pagesPayload(config.PagesConfig{Enabled: true}, "")
+	if body["build_type"] != "workflow" {
+		t.Errorf("build_type = %v, want workflow", body["build_type"])
+	}
+	if _, ok := body["source"]; ok {
+		t.Error("workflow builds should not send a source")
+	}
+}
+
+func TestPagesPayload_LegacyDefaults(t *testing.T) {
+	body := pagesPayload(config.PagesConfig{Enabled: true, BuildType: "legacy"}, "trunk")
+	source := body["source"].(map[string]string)
+	if source["branch"] != "trunk" || source["path"] != "/" {
+		t.Errorf("source = %v, want trunk at /", source)
+	}
+}
+
+func TestPagesUpdatePayload(t *testing.T) {
+	tr := true
+	p := config.PagesConfig{Enabled: true, CNAME: "{{.Name}}.docs.acme.com", HTTPSEnforced: &tr}
+	body, err := pagesUpdatePayload(p, "acme/Widget")
+	if err != nil {
+		t.Fatalf("pagesUpdatePayload: %v", err)
+	}
+	if body["cname"] != "widget.docs.acme.com" {
+		t.Errorf("cname = %v", body["cname"])
+	}
+	if body["https_enforced"] != true {
+		t.Errorf("https_enforced = %v", body["https_enforced"])
+	}
+
+	body, err = pagesUpdatePayload(config.PagesConfig{Enabled: true}, "acme/widget")
+	if err != nil || body != nil {
+		t.Errorf("expected nil body without cname or https, got %v, %v", body, err)
+	}
+}
+
+func TestConfigurePages_EnablesOnlyWhenMissing(t *testing.T) {
+	tests := []struct {
+		name    string
+		get     int
+		want    []string
+		wantErr bool
+	}{
+		{"not enabled", http.StatusNotFound, []string{"GET", "POST"}, false},
+		{"already enabled", http.StatusOK, []string{"GET", "PUT"}, false},
+		{"lookup fails", http.StatusForbidden, []string{"GET"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+				if r.Method == "GET" {
+					return tt.get, `{}`
+				}
+				return http.StatusOK, `{}`
+			})
+			err := c.ConfigurePages("acme/widget", config.PagesConfig{Enabled: true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConfigurePages error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var methods []string
+			for _, r := range api.recorded() {
+				methods = append(methods, r.Method)
+			}
+			if !reflect.DeepEqual(methods, tt.want) {
+				t.Errorf("requests = %v, want %v", methods, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package github
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultConcurrency is how many independent steps run at once.
+const defaultConcurrency = 4
+
+// pipelineStep is one named unit of work. A step runs once every step in
+// after has succeeded, and is skipped if any of them failed or was skipped.
+type pipelineStep struct {
+	id    string
+	name  string
+	after []string
+	run   func(r *stepRun) []error
+}
+
+// PlanStep describes a pipeline step for inspection.
+type PlanStep struct {
+	ID        string   `json:"id" yaml:"id"`
+	Name      string   `json:"name" yaml:"name"`
+	DependsOn []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+}
+
+// newPlan drops dependencies on steps that aren't in the plan, and panics if
+// a step depends on one declared after it: declaration order must already
+// be a valid run order, which also rules out cycles.
+func newPlan(steps []pipelineStep) []pipelineStep {
+	declared := make(map[string]bool, len(steps))
+	all := make(map[string]bool, len(steps))
+	for _, s := range steps {
+		all[s.id] = true
+	}
+	plan := make([]pipelineStep, 0, len(steps))
+	for _, s := range steps {
+		var after []string
+		for _, dep := range s.after {
+			if !all[dep] {
+				continue
+			}
+			if !declared[dep] {
+				panic(fmt.Sprintf("pipeline step %q depends on later step %q", s.id, dep))
+			}
+			after = append(after, dep)
+		}
+		s.after = after
+		plan = append(plan, s)
+		declared[s.id] = true
+	}
+	return plan
+}
+
+func describePlan(steps []pipelineStep) []PlanStep {
+	plan := make([]PlanStep, len(steps))
+	for i, s := range steps {
+		plan[i] = PlanStep{ID: s.id, Name: s.name, DependsOn: s.after}
+	}
+	return plan
+}
+
+// pipelineRun holds the state shared by the steps of one run. Steps run
+// concurrently, so progress reports and the journal go through mu.
+type pipelineRun struct {
+	c    *Client
+	opts *CreateOpts
+	nwo  string
+	dir  string // the local project being published, if any
+
+	mu       sync.Mutex
+	applied  []string // names of repo changes reported as succeeded, for rollback
+	keyFiles []string // private keys written for the repo's deploy keys
+
+	// Written by one step and read only by steps that depend on it.
+	seed        seedProgress
+	templateSHA string
+	scaffoldSHA string
+}
+
+func (r *pipelineRun) report(name string, err error) {
+	r.reportTimed(name, "", err, 0)
+}
+
+func (r *pipelineRun) reportTimed(name, output string, err error, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		r.applied = append(r.applied, name)
+	}
+	r.opts.reportOutput(name, output, err, elapsed)
+}
+
+// reportLocal reports a change to the local machine, such as a command or
+// a clone, which deleting the repo doesn't undo.
+func (r *pipelineRun) reportLocal(name, output string, err error, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.opts.reportOutput(name, output, err, elapsed)
+}
+
+// stepRun is the run as one step sees it. Each of its reports carries the
+// time since the step started or last reported, so a step that reports
+// several results times each one.
+type stepRun struct {
+	*pipelineRun
+	last time.Time
+}
+
+func (s *stepRun) report(name string, err error) {
+	s.reportOutput(name, "", err)
+}
+
+// reportOutput reports a local command the step ran with its output, or a
+// result with a note.
+func (s *stepRun) reportOutput(name, output string, err error) {
+	now := time.Now()
+	s.reportTimed(name, output, err, now.Sub(s.last))
+	s.last = now
+}
+
+// reportLocalOutput is reportOutput for a change rollback can't undo.
+func (s *stepRun) reportLocalOutput(name, output string, err error) {
+	now := time.Now()
+	s.reportLocal(name, output, err, now.Sub(s.last))
+	s.last = now
+}
+
+// skip reports a step that won't run and journals it, so a resume retries
+// it.
+func (r *pipelineRun) skip(id, name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.opts.OnProgress != nil {
+		r.opts.OnProgress(StepStatus{Name: name, Skipped: true, Err: err, Message: err.Error()})
+	}
+	if j := r.opts.Journal; j != nil {
+		if jerr := j.Skip(id, name, err.Error()); jerr != nil {
+			r.opts.reportOutput("Saved run journal", "", jerr, 0)
+		}
+	}
+}
+
+// expect journals the steps about to run as pending.
+func (r *pipelineRun) expect(steps []pipelineStep) {
+	if r.opts.Journal == nil {
+		return
+	}
+	ids := make([]string, len(steps))
+	names := make([]string, len(steps))
+	for i, s := range steps {
+		ids[i], names[i] = s.id, s.name
+	}
+	r.mu.Lock()
+	err := r.opts.Journal.Expect(ids, names)
+	r.mu.Unlock()
+	if err != nil {
+		r.report("Saved run journal", err)
+	}
+}
+
+// record writes a step outcome to the journal, if there is one. A journal
+// that can't be saved is reported but does not fail the run.
+func (r *pipelineRun) record(id, name string, err error) {
+	if r.opts.Journal == nil {
+		return
+	}
+	r.mu.Lock()
+	jerr := r.opts.Journal.Record(id, name, err)
+	r.mu.Unlock()
+	if jerr != nil {
+		r.report("Saved run journal", jerr)
+	}
+}
+
+func (r *pipelineRun) output(id string, v interface{}) {
+	if r.opts.Journal == nil {
+		return
+	}
+	r.mu.Lock()
+	err := r.opts.Journal.SetOutput(id, v)
+	r.mu.Unlock()
+	if err != nil {
+		r.report("Saved run journal", err)
+	}
+}
+
+// savedOutput reads a step's output from an earlier attempt, if any.
+func (r *pipelineRun) savedOutput(id string, v interface{}) {
+	if r.opts.Journal == nil {
+		return
+	}
+	r.mu.Lock()
+	_, err := r.opts.Journal.Output(id, v)
+	r.mu.Unlock()
+	if err != nil {
+		r.report("Read run journal", err)
+	}
+}
+
+func (r *pipelineRun) done(id string) bool {
+	if r.opts.Journal == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.opts.Journal.Succeeded(id)
+}
+
+func (r *pipelineRun) attempted(id string) bool {
+	if r.opts.Journal == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.opts.Journal.Attempted(id)
+}
+
+type stepOutcome struct {
+	id   string
+	errs []error
+}
+
+// execute runs the plan, starting each step as soon as its dependencies
+// have succeeded, with up to concurrency steps in flight. Steps the journal
+// already records as succeeded count as done without running; the rest
+// are journaled as pending until they finish or are skipped. It returns
+// the errors of failed steps and the number of steps skipped.
+func (r *pipelineRun) execute(steps []pipelineStep, concurrency int) (errs []error, skipped int) {
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+	r.expect(steps)
+	names := make(map[string]string, len(steps))
+	for _, s := range steps {
+		names[s.id] = s.name
+	}
+	started := make(map[string]bool, len(steps))
+	finished := make(map[string]bool, len(steps))
+	// failedBecause maps a failed or skipped step to the name of the step
+	// that failed first along its dependency chain.
+	failedBecause := make(map[string]string)
+	results := make(chan stepOutcome)
+	running := 0
+
+	for len(finished) < len(steps) {
+		progressed := false
+		for _, s := range steps {
+			if started[s.id] {
+				continue
+			}
+			ready, cause := true, ""
+			for _, dep := range s.after {
+				if !finished[dep] {
+					ready = false
+					break
+				}
+				if c, ok := failedBecause[dep]; ok {
+					cause = c
+					break
+				}
+			}
+			switch {
+			case cause != "":
+				started[s.id], finished[s.id] = true, true
+				failedBecause[s.id] = cause
+				r.skip(s.id, s.name, fmt.Errorf("skipped because %s failed", cause))
+				skipped++
+				progressed = true
+			case !ready || running >= concurrency:
+			case r.done(s.id):
+				started[s.id], finished[s.id] = true, true
+				progressed = true
+			default:
+				started[s.id] = true
+				running++
+				go func(s pipelineStep) {
+					results <- stepOutcome{id: s.id, errs: r.runStep(s)}
+				}(s)
+			}
+		}
+		if progressed {
+			continue
+		}
+		if running == 0 {
+			break
+		}
+		out := <-results
+		running--
+		finished[out.id] = true
+		if len(out.errs) > 0 {
+			failedBecause[out.id] = names[out.id]
+			errs = append(errs, out.errs...)
+		}
+	}
+	return errs, skipped
+}
+
+func (r *pipelineRun) runStep(s pipelineStep) []error {
+	errs := s.run(&stepRun{pipelineRun: r, last: time.Now()})
+	var err error
+	if len(errs) > 0 {
+		err = errs[0]
+	}
+	r.record(s.id, s.name, err)
+	return errs
+}
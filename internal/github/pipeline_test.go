@@ -0,0 +1,259 @@
+package github
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	"github.com/ggfevans/gh-mint/internal/journal"
+)
+
+func TestNewPlan_DropsAbsentDependencies(t *testing.T) {
+	plan := newPlan([]pipelineStep{
+		{id: "a"},
+		{id: "b", after: []string{"a", "missing"}},
+	})
+	if got := plan[1].after; !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("after = %v, want [a]", got)
+	}
+}
+
+func TestNewPlan_PanicsOnForwardDependency(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a dependency on a later step")
+		}
+	}()
+	newPlan([]pipelineStep{
+		{id: "a", after: []string{"b"}},
+		{id: "b"},
+	})
+}
+
+// fakeStep succeeds unless err is set, and records that it ran.
+func fakeStep(id string, ran *sync.Map, err error, after ...string) pipelineStep {
+	return pipelineStep{id: id, name: "Step " + id, after: after, run: func(r *stepRun) []error {
+		ran.Store(id, true)
+		r.report("Step "+id, err)
+		return errList(err)
+	}}
+}
+
+func ranIDs(ran *sync.Map) []string {
+	var ids []string
+	ran.Range(func(k, _ interface{}) bool {
+		ids = append(ids, k.(string))
+		return true
+	})
+	sort.Strings(ids)
+	return ids
+}
+
+func TestExecute_SkipsDependentsOfFailedSteps(t *testing.T) {
+	var ran sync.Map
+	var statuses []StepStatus
+	opts := &CreateOpts{OnProgress: func(s StepStatus) { statuses = append(statuses, s) }}
+	r := &pipelineRun{opts: opts}
+
+	errs, skipped := r.execute(newPlan([]pipelineStep{
+		fakeStep("a", &ran, errors.New("boom")),
+		fakeStep("b", &ran, nil),
+		fakeStep("c", &ran, nil, "a"),
+		fakeStep("d", &ran, nil, "c", "b"),
+	}), 2)
+
+	if len(errs) != 1 || skipped != 2 {
+		t.Fatalf("errs = %v, skipped = %d, want 1 error and 2 skipped", errs, skipped)
+	}
+	if got := ranIDs(&ran); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("ran %v, want [a b]", got)
+	}
+	var skips []string
+	for _, s := range statuses {
+		if s.Skipped {
+			skips = append(skips, s.Name+": "+s.Message)
+		}
+	}
+	sort.Strings(skips)
+	want := []string{
+		"Step c: skipped because Step a failed",
+		"Step d: skipped because Step a failed",
+	}
+	if !reflect.DeepEqual(skips, want) {
+		t.Errorf("skips = %q, want %q", skips, want)
+	}
+	if !reflect.DeepEqual(r.applied, []string{"Step b"}) {
+		t.Errorf("applied = %v, want [Step b]", r.applied)
+	}
+}
+
+func TestReportLocal_NotUndoneByRollback(t *testing.T) {
+	var statuses []StepStatus
+	r := &stepRun{pipelineRun: &pipelineRun{opts: &CreateOpts{OnProgress: func(s StepStatus) {
+		statuses = append(statuses, s)
+	}}}}
+	r.report("Set topics", nil)
+	r.reportLocalOutput("Ran post_clone: make setup", "ok\n", nil)
+	if !reflect.DeepEqual(r.applied, []string{"Set topics"}) {
+		t.Errorf("applied = %v, want only the repo change", r.applied)
+	}
+	if len(statuses) != 2 || !statuses[1].Success || statuses[1].Output != "ok\n" {
+		t.Errorf("statuses = %+v", statuses)
+	}
+}
+
+func TestExecute_RespectsConcurrency(t *testing.T) {
+	var inFlight, peak int32
+	step := func(id string) pipelineStep {
+		return pipelineStep{id: id, run: func(r *stepRun) []error {
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			atomic.AddInt32(&inFlight, -1)
+			return nil
+		}}
+	}
+	var steps []pipelineStep
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		steps = append(steps, step(id))
+	}
+	r := &pipelineRun{opts: &CreateOpts{}}
+	if errs, _ := r.execute(newPlan(steps), 1); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if peak != 1 {
+		t.Errorf("peak concurrency = %d, want 1", peak)
+	}
+}
+
+func TestExecute_SkipsStepsTheJournalRecordsAsDone(t *testing.T) {
+	run, err := journal.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := run.Record("a", "Step a", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := run.Record("b", "Step b", errors.New("earlier failure")); err != nil {
+		t.Fatal(err)
+	}
+
+	var ran sync.Map
+	r := &pipelineRun{opts: &CreateOpts{Journal: run}}
+	errs, skipped := r.execute(newPlan([]pipelineStep{
+		fakeStep("a", &ran, nil),
+		fakeStep("b", &ran, nil, "a"),
+	}), 0)
+	if len(errs) != 0 || skipped != 0 {
+		t.Fatalf("errs = %v, skipped = %d", errs, skipped)
+	}
+	if got := ranIDs(&ran); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("ran %v, want [b]", got)
+	}
+	if !run.Succeeded("b") {
+		t.Error("retried step b should be recorded as succeeded")
+	}
+}
+
+func TestExecute_JournalsSkippedAndPendingSteps(t *testing.T) {
+	dir := t.TempDir()
+	run, err := journal.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ran sync.Map
+	var loaded *journal.Run
+	r := &pipelineRun{opts: &CreateOpts{Journal: run}}
+	r.execute(newPlan([]pipelineStep{
+		fakeStep("a", &ran, errors.New("boom")),
+		fakeStep("b", &ran, nil, "a"),
+		{id: "c", name: "Step c", run: func(r *stepRun) []error {
+			// What an interrupted run leaves on disk while c runs.
+			loaded, err = journal.Load(dir, run.ID)
+			return nil
+		}},
+	}), 1)
+
+	if err != nil || loaded.Attempted("c") || len(loaded.Unfinished()) != 3 {
+		t.Errorf("mid-run journal = %+v, %v; want every step pending until recorded", loaded, err)
+	}
+	if !loaded.Resumable() {
+		t.Error("an interrupted run should be resumable")
+	}
+	steps := run.Unfinished()
+	if len(steps) != 2 || steps[1].ID != "b" || steps[1].Status != journal.Skipped || steps[1].Error != "skipped because Step a failed" {
+		t.Errorf("unfinished = %+v, want a failed and b skipped", steps)
+	}
+}
+
+func planIDs(plan []PlanStep) []string {
+	ids := make([]string, len(plan))
+	for i, s := range plan {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func TestCreateAndApplyPlans(t *testing.T) {
+	opts := CreateOpts{Profile: config.Profile{
+		Seed:             config.SeedConfig{Issues: []config.SeedIssue{{Title: "Roadmap"}}},
+		Project:          config.ProjectConfig{Number: 1},
+		Boilerplate:      config.BoilerplateConfig{Files: []config.BoilerplateFile{{Src: "a", Dest: "b"}}},
+		BranchProtection: config.BranchProtection{Branch: "main"},
+	}}
+
+	create := CreatePlan(opts)
+	wantCreate := []string{"settings", "security", "labels", "seed", "project", "actions-policy", "boilerplate", "branch-protection"}
+	if got := planIDs(create); !reflect.DeepEqual(got, wantCreate) {
+		t.Errorf("create plan = %v, want %v", got, wantCreate)
+	}
+	for _, s := range create {
+		if s.ID == "seed" && !reflect.DeepEqual(s.DependsOn, []string{"settings", "labels"}) {
+			t.Errorf("seed depends on %v, want [settings labels]", s.DependsOn)
+		}
+		if s.ID == "boilerplate" && !reflect.DeepEqual(s.DependsOn, []string{"actions-policy"}) {
+			t.Errorf("boilerplate depends on %v, want [actions-policy]", s.DependsOn)
+		}
+	}
+
+	apply := ApplyPlan(opts)
+	wantApply := []string{"settings", "security", "labels", "project", "actions-policy", "branch-protection"}
+	if got := planIDs(apply); !reflect.DeepEqual(got, wantApply) {
+		t.Errorf("apply plan = %v, want %v", got, wantApply)
+	}
+	for _, s := range apply {
+		if len(s.DependsOn) > 0 {
+			t.Errorf("apply step %s depends on %v, want none", s.ID, s.DependsOn)
+		}
+	}
+}
+
+func TestCreatePlan_WaitsForTemplate(t *testing.T) {
+	opts := CreateOpts{Template: "acme/go-template", Profile: config.Profile{
+		Boilerplate:      config.BoilerplateConfig{Files: []config.BoilerplateFile{{Src: "a", Dest: "b"}}},
+		BranchProtection: config.BranchProtection{Branch: "main"},
+	}}
+	deps := make(map[string][]string)
+	for _, s := range CreatePlan(opts) {
+		deps[s.ID] = s.DependsOn
+	}
+	if got := deps["boilerplate"]; !reflect.DeepEqual(got, []string{"contents", "actions-policy"}) {
+		t.Errorf("boilerplate depends on %v, want [contents actions-policy]", got)
+	}
+	if got := deps["branch-protection"]; !reflect.DeepEqual(got, []string{"contents", "boilerplate"}) {
+		t.Errorf("branch-protection depends on %v, want [contents boilerplate]", got)
+	}
+	for _, s := range ApplyPlan(opts) {
+		if s.ID == "contents" {
+			t.Error("apply should not wait for a template")
+		}
+	}
+}
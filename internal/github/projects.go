@@ -0,0 +1,221 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// project is a Projects (v2) board.
+type project struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Number int    `json:"number"`
+}
+
+const projectByNumberQuery = `query($login: String!, $number: Int!) {
+  repositoryOwner(login: $login) {
+    ... on ProjectV2Owner { projectV2(number: $number) { id title number } }
+  }
+}`
+
+const projectSearchQuery = `query($login: String!, $query: String!) {
+  repositoryOwner(login: $login) {
+    ... on ProjectV2Owner { projectsV2(first: 100, query: $query) { nodes { id title number } } }
+  }
+}`
+
+const repositoryIDQuery = `query($owner: String!, $name: String!) { repository(owner: $owner, name: $name) { id } }`
+
+const linkProjectMutation = `mutation($project: ID!, $repo: ID!) {
+  linkProjectV2ToRepository(input: {projectId: $project, repositoryId: $repo}) { repository { id } }
+}`
+
+const projectStatusFieldQuery = `query($id: ID!, $field: String!) {
+  node(id: $id) {
+    ... on ProjectV2 { field(name: $field) { ... on ProjectV2SingleSelectField { id options { id name } } } }
+  }
+}`
+
+const addProjectItemMutation = `mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`
+
+const setProjectStatusMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) { projectV2Item { id } }
+}`
+
+// matchProjectTitle picks the project whose title matches exactly, ignoring
+// case. The search API matches loosely, so several results are normal.
+func matchProjectTitle(candidates []project, title string) (project, error) {
+	var matches []project
+	for _, p := range candidates {
+		if strings.EqualFold(p.Title, title) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return project{}, fmt.Errorf("no project titled %q", title)
+	case 1:
+		return matches[0], nil
+	}
+	numbers := make([]string, len(matches))
+	for i, p := range matches {
+		numbers[i] = fmt.Sprintf("#%d", p.Number)
+	}
+	return project{}, fmt.Errorf("several projects are titled %q (%s); use number instead", title, strings.Join(numbers, ", "))
+}
+
+type fieldOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func matchFieldOption(options []fieldOption, name string) (string, error) {
+	names := make([]string, len(options))
+	for i, o := range options {
+		if strings.EqualFold(o.Name, name) {
+			return o.ID, nil
+		}
+		names[i] = o.Name
+	}
+	return "", fmt.Errorf("status %q must be one of: %s", name, strings.Join(names, ", "))
+}
+
+// FindProject looks up an owner's project by number or title.
+func (c *Client) FindProject(owner string, cfg config.ProjectConfig) (project, error) {
+	if cfg.Number != 0 {
+		var data struct {
+			RepositoryOwner *struct {
+				ProjectV2 *project `json:"projectV2"`
+			} `json:"repositoryOwner"`
+		}
+		vars := map[string]interface{}{"login": owner, "number": cfg.Number}
+		if err := c.graphql(projectByNumberQuery, vars, &data); err != nil {
+			return project{}, fmt.Errorf("finding project %s/%d: %w", owner, cfg.Number, err)
+		}
+		if data.RepositoryOwner == nil || data.RepositoryOwner.ProjectV2 == nil {
+			return project{}, fmt.Errorf("project %s/%d not found", owner, cfg.Number)
+		}
+		return *data.RepositoryOwner.ProjectV2, nil
+	}
+
+	var data struct {
+		RepositoryOwner *struct {
+			ProjectsV2 struct {
+				Nodes []project `json:"nodes"`
+			} `json:"projectsV2"`
+		} `json:"repositoryOwner"`
+	}
+	vars := map[string]interface{}{"login": owner, "query": cfg.Title}
+	if err := c.graphql(projectSearchQuery, vars, &data); err != nil {
+		return project{}, fmt.Errorf("finding project %q: %w", cfg.Title, err)
+	}
+	if data.RepositoryOwner == nil {
+		return project{}, fmt.Errorf("owner %s not found", owner)
+	}
+	p, err := matchProjectTitle(data.RepositoryOwner.ProjectsV2.Nodes, cfg.Title)
+	if err != nil {
+		return project{}, fmt.Errorf("%s: %w", owner, err)
+	}
+	return p, nil
+}
+
+// LinkProject links the repo to the profile's project and returns the
+// project. The project owner defaults to the repo owner.
+func (c *Client) LinkProject(nwo string, cfg config.ProjectConfig) (project, error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return project{}, fmt.Errorf("invalid nwo: %w", err)
+	}
+	vars := config.NewRepoVars(nwo)
+	owner := cfg.Owner
+	if owner == "" {
+		owner = vars.Owner
+	}
+	p, err := c.FindProject(owner, cfg)
+	if err != nil {
+		return project{}, err
+	}
+
+	var repo struct {
+		Repository struct {
+			ID string `json:"id"`
+		} `json:"repository"`
+	}
+	if err := c.graphql(repositoryIDQuery, map[string]interface{}{"owner": vars.Owner, "name": vars.Name}, &repo); err != nil {
+		return p, fmt.Errorf("looking up repository id: %w", err)
+	}
+	link := map[string]interface{}{"project": p.ID, "repo": repo.Repository.ID}
+	if err := c.graphql(linkProjectMutation, link, nil); err != nil {
+		return p, fmt.Errorf("linking project %q: %w", p.Title, err)
+	}
+	return p, nil
+}
+
+// projectStatus resolves the field and option IDs for the configured status.
+func (c *Client) projectStatus(projectID string, cfg config.ProjectConfig) (fieldID, optionID string, err error) {
+	var data struct {
+		Node struct {
+			Field *struct {
+				ID      string        `json:"id"`
+				Options []fieldOption `json:"options"`
+			} `json:"field"`
+		} `json:"node"`
+	}
+	vars := map[string]interface{}{"id": projectID, "field": cfg.Field()}
+	if err := c.graphql(projectStatusFieldQuery, vars, &data); err != nil {
+		return "", "", fmt.Errorf("looking up project field %q: %w", cfg.Field(), err)
+	}
+	if data.Node.Field == nil || data.Node.Field.ID == "" {
+		return "", "", fmt.Errorf("project field %q is not a single-select field", cfg.Field())
+	}
+	optionID, err = matchFieldOption(data.Node.Field.Options, cfg.Status)
+	if err != nil {
+		return "", "", err
+	}
+	return data.Node.Field.ID, optionID, nil
+}
+
+// AddIssuesToProject adds issues to the project and, when the profile sets
+// a status, sets it on each new item. Returns the number of issues added.
+func (c *Client) AddIssuesToProject(p project, cfg config.ProjectConfig, issues []SeededIssue) (added int, errs []error) {
+	var fieldID, optionID string
+	if cfg.Status != "" {
+		var err error
+		fieldID, optionID, err = c.projectStatus(p.ID, cfg)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+	}
+	for _, issue := range issues {
+		var data struct {
+			AddProjectV2ItemById struct {
+				Item struct {
+					ID string `json:"id"`
+				} `json:"item"`
+			} `json:"addProjectV2ItemById"`
+		}
+		vars := map[string]interface{}{"project": p.ID, "content": issue.NodeID}
+		if err := c.graphql(addProjectItemMutation, vars, &data); err != nil {
+			errs = append(errs, fmt.Errorf("adding issue #%d to project: %w", issue.Number, err))
+			continue
+		}
+		added++
+		if optionID == "" {
+			continue
+		}
+		vars = map[string]interface{}{
+			"project": p.ID,
+			"item":    data.AddProjectV2ItemById.Item.ID,
+			"field":   fieldID,
+			"option":  optionID,
+		}
+		if err := c.graphql(setProjectStatusMutation, vars, nil); err != nil {
+			errs = append(errs, fmt.Errorf("setting status on issue #%d: %w", issue.Number, err))
+		}
+	}
+	return
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestMatchProjectTitle(t *testing.T) {
+	candidates := []project{
+		{ID: "P_1", Title: "Roadmap 2024", Number: 1},
+		{ID: "P_2", Title: "Roadmap", Number: 2},
+		{ID: "P_3", Title: "Team board", Number: 3},
+		{ID: "P_4", Title: "team board", Number: 4},
+	}
+	p, err := matchProjectTitle(candidates, "roadmap")
+	if err != nil || p.ID != "P_2" {
+		t.Errorf("matchProjectTitle(roadmap) = %+v, %v", p, err)
+	}
+	if _, err := matchProjectTitle(candidates, "Backlog"); err == nil {
+		t.Error("expected error for unknown title")
+	}
+	_, err = matchProjectTitle(candidates, "Team board")
+	if err == nil || !strings.Contains(err.Error(), "#3, #4") {
+		t.Errorf("expected ambiguity error listing numbers, got %v", err)
+	}
+}
+
+func TestMatchFieldOption(t *testing.T) {
+	options := []fieldOption{{ID: "o1", Name: "Todo"}, {ID: "o2", Name: "In Progress"}}
+	if id, err := matchFieldOption(options, "in progress"); err != nil || id != "o2" {
+		t.Errorf("matchFieldOption = %q, %v", id, err)
+	}
+	if _, err := matchFieldOption(options, "Done"); err == nil || !strings.Contains(err.Error(), "Todo, In Progress") {
+		t.Errorf("expected error listing options, got %v", err)
+	}
+}
+
+func TestLinkProjectAndAddIssues_FakeEndpoint(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		switch {
+		case strings.Contains(r.Body, "projectsV2("):
+			return http.StatusOK, `{"data": {"repositoryOwner": {"projectsV2": {"nodes": [{"id": "P_9", "title": "Roadmap", "number": 9}]}}}}`
+		case strings.Contains(r.Body, "repository(owner"):
+			return http.StatusOK, `{"data": {"repository": {"id": "R_1"}}}`
+		case strings.Contains(r.Body, "linkProjectV2ToRepository"):
+			return http.StatusOK, `{"data": {"linkProjectV2ToRepository": {"repository": {"id": "R_1"}}}}`
+		case strings.Contains(r.Body, "field(name"):
+			return http.StatusOK, `{"data": {"node": {"field": {"id": "F_1", "options": [{"id": "o1", "name": "Todo"}]}}}}`
+		case strings.Contains(r.Body, "addProjectV2ItemById"):
+			return http.StatusOK, `{"data": {"addProjectV2ItemById": {"item": {"id": "PVTI_1"}}}}`
+		case strings.Contains(r.Body, "updateProjectV2ItemFieldValue"):
+			return http.StatusOK, `{"data": {"updateProjectV2ItemFieldValue": {"projectV2Item": {"id": "PVTI_1"}}}}`
+		}
+		return http.StatusNotFound, ""
+	})
+
+	cfg := config.ProjectConfig{Owner: "acme-eng", Title: "Roadmap", AddSeededIssues: true, Status: "todo"}
+	p, err := c.LinkProject("acme/widget", cfg)
+	if err != nil {
+		t.Fatalf("LinkProject: %v", err)
+	}
+	if p.ID != "P_9" {
+		t.Errorf("project = %+v", p)
+	}
+	added, errs := c.AddIssuesToProject(p, cfg, []SeededIssue{{Number: 1, NodeID: "I_1"}})
+	if len(errs) > 0 || added != 1 {
+		t.Fatalf("AddIssuesToProject = %d, %v", added, errs)
+	}
+
+	reqs := api.recorded()
+	if len(reqs) != 6 {
+		t.Fatalf("expected 6 requests, got %d: %+v", len(reqs), reqs)
+	}
+	var search struct {
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.Unmarshal([]byte(reqs[0].Body), &search); err != nil || search.Variables["login"] != "acme-eng" {
+		t.Errorf("search body = %s", reqs[0].Body)
+	}
+	var update struct {
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.Unmarshal([]byte(reqs[5].Body), &update); err != nil {
+		t.Fatalf("parsing update body: %v", err)
+	}
+	if update.Variables["item"] != "PVTI_1" || update.Variables["field"] != "F_1" || update.Variables["option"] != "o1" {
+		t.Errorf("update variables = %v", update.Variables)
+	}
+}
@@ -0,0 +1,192 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// propertySchemaTTL is how long a fetched org property schema is reused
+// from the on-disk cache.
+const propertySchemaTTL = time.Hour
+
+// PropertySchema is one organisation custom property definition.
+type PropertySchema struct {
+	Name          string      `json:"property_name"`
+	ValueType     string      `json:"value_type"`
+	Required      bool        `json:"required"`
+	DefaultValue  interface{} `json:"default_value"`
+	AllowedValues []string    `json:"allowed_values"`
+}
+
+func (s PropertySchema) hasDefault() bool {
+	switch v := s.DefaultValue.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// validateCustomProperties checks props against the org schema: every key
+// must be defined, values must match the property type, and required
+// properties without a default must be set.
+func validateCustomProperties(schema []PropertySchema, props map[string]config.PropertyValue) error {
+	defs := make(map[string]PropertySchema, len(schema))
+	for _, s := range schema {
+		defs[s.Name] = s
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		def, ok := defs[name]
+		if !ok {
+			return fmt.Errorf("custom property %q is not defined by the organisation", name)
+		}
+		value := props[name]
+		if def.ValueType != "multi_select" && len(value) != 1 {
+			return fmt.Errorf("custom property %q takes a single value", name)
+		}
+		for _, v := range value {
+			switch def.ValueType {
+			case "single_select", "multi_select":
+				if !slices.Contains(def.AllowedValues, v) {
+					return fmt.Errorf("custom property %q value %q must be one of: %s", name, v, strings.Join(def.AllowedValues, ", "))
+				}
+			case "true_false":
+				if v != "true" && v != "false" {
+					return fmt.Errorf("custom property %q must be true or false", name)
+				}
+			case "url":
+				if err := config.ValidateHomepage(v); err != nil {
+					return fmt.Errorf("custom property %q must be an http(s) URL", name)
+				}
+			}
+		}
+	}
+
+	for _, s := range schema {
+		if _, ok := props[s.Name]; !ok && s.Required && !s.hasDefault() {
+			return fmt.Errorf("custom property %q is required by the organisation", s.Name)
+		}
+	}
+	return nil
+}
+
+// customPropertiesPayload builds the body for the property values PATCH.
+// multi_select values are sent as arrays, everything else as a string.
+func customPropertiesPayload(schema []PropertySchema, props map[string]config.PropertyValue) map[string]interface{} {
+	types := make(map[string]string, len(schema))
+	for _, s := range schema {
+		types[s.Name] = s.ValueType
+	}
+
+	values := make([]map[string]interface{}, 0, len(props))
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		var value interface{} = []string(props[name])
+		if types[name] != "multi_select" {
+			value = props[name][0]
+		}
+		values = append(values, map[string]interface{}{"property_name": name, "value": value})
+	}
+	return map[string]interface{}{"properties": values}
+}
+
+func (c *Client) schemaCachePath(org string) string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	return filepath.Join(c.cacheDir, "properties", org+".json")
+}
+
+func readSchemaCache(path string) ([]PropertySchema, bool) {
+	if path == "" {
+		return nil, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > propertySchemaTTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var schema []PropertySchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, false
+	}
+	return schema, true
+}
+
+func writeSchemaCache(path string, schema []PropertySchema) {
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0600)
+}
+
+// PropertySchema returns the org's custom property definitions, from memory
+// or the on-disk cache when fresh, otherwise from the API.
+func (c *Client) PropertySchema(org string) ([]PropertySchema, error) {
+	if schema, ok := c.schemas[org]; ok {
+		return schema, nil
+	}
+	if err := config.ValidateRepoName(org); err != nil {
+		return nil, fmt.Errorf("invalid org: %w", err)
+	}
+
+	path := c.schemaCachePath(org)
+	schema, ok := readSchemaCache(path)
+	if !ok {
+		out, err := c.api("GET", fmt.Sprintf("orgs/%s/properties/schema", org), nil)
+		if err != nil {
+			return nil, fmt.Errorf("fetching custom property schema: %w", err)
+		}
+		if err := json.Unmarshal([]byte(out), &schema); err != nil {
+			return nil, fmt.Errorf("parsing custom property schema: %w", err)
+		}
+		writeSchemaCache(path, schema)
+	}
+
+	if c.schemas == nil {
+		c.schemas = make(map[string][]PropertySchema)
+	}
+	c.schemas[org] = schema
+	return schema, nil
+}
+
+// SetCustomProperties validates and sets custom property values on a repo.
+func (c *Client) SetCustomProperties(nwo string, props map[string]config.PropertyValue) error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return fmt.Errorf("invalid nwo: %w", err)
+	}
+	org := config.NewRepoVars(nwo).Owner
+	schema, err := c.PropertySchema(org)
+	if err != nil {
+		return err
+	}
+	if err := validateCustomProperties(schema, props); err != nil {
+		return err
+	}
+	body := customPropertiesPayload(schema, props)
+	if _, err := c.api("PATCH", fmt.Sprintf("repos/%s/properties/values", nwo), body); err != nil {
+		return fmt.Errorf("setting custom properties: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package github
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+var testSchema = []PropertySchema{
+	{Name: "team", ValueType: "string", Required: true},
+	{Name: "tier", ValueType: "single_select", AllowedValues: []string{"1", "2", "3"}},
+	{Name: "data-classification", ValueType: "multi_select", AllowedValues: []string{"pii", "internal", "public"}},
+	{Name: "archived", ValueType: "true_false", Required: true, DefaultValue: "false"},
+}
+
+func TestValidateCustomProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		props   map[string]config.PropertyValue
+		wantErr bool
+	}{
+		{"valid", map[string]config.PropertyValue{"team": {"platform"}, "tier": {"1"}, "data-classification": {"pii", "internal"}}, false},
+		{"unknown key", map[string]config.PropertyValue{"team": {"platform"}, "owner": {"me"}}, true},
+		{"disallowed select value", map[string]config.PropertyValue{"team": {"platform"}, "tier": {"4"}}, true},
+		{"disallowed multi value", map[string]config.PropertyValue{"team": {"platform"}, "data-classification": {"secret"}}, true},
+		{"list for single value", map[string]config.PropertyValue{"team": {"a", "b"}}, true},
+		{"bad boolean", map[string]config.PropertyValue{"team": {"platform"}, "archived": {"yes"}}, true},
+		{"missing required", map[string]config.PropertyValue{"tier": {"1"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCustomProperties(testSchema, tt.props)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCustomProperties() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustomPropertiesPayload(t *testing.T) {
+	props := map[string]config.PropertyValue{
+		"tier":                {"2"},
+		"data-classification": {"pii"},
+	}
+	payload := customPropertiesPayload(testSchema, props)
+	values := payload["properties"].([]map[string]interface{})
+	if len(values) != 2 {
+		t.Fatalf("expected 2 values, got %d", len(values))
+	}
+	// Sorted by name
+	if values[0]["property_name"] != "data-classification" {
+		t.Errorf("first property = %v", values[0]["property_name"])
+	}
+	if !reflect.DeepEqual(values[0]["value"], []string{"pii"}) {
+		t.Errorf("multi_select value = %#v, want array", values[0]["value"])
+	}
+	if values[1]["value"] != "2" {
+		t.Errorf("single_select value = %#v, want string", values[1]["value"])
+	}
+}
+
+func TestSchemaCache_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "properties", "acme.json")
+	if _, ok := readSchemaCache(path); ok {
+		t.Fatal("expected cache miss before write")
+	}
+	writeSchemaCache(path, testSchema)
+	schema, ok := readSchemaCache(path)
+	if !ok {
+		t.Fatal("expected cache hit after write")
+	}
+	if len(schema) != len(testSchema) || schema[1].AllowedValues[2] != "3" {
+		t.Errorf("cached schema = %+v", schema)
+	}
+}
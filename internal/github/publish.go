@@ -0,0 +1,310 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+	"github.com/ggfevans/gh-mint/internal/scaffold"
+)
+
+// localRepo describes the directory being published.
+type localRepo struct {
+	exists bool   // the directory is already a git repository
+	branch string // its checked-out branch
+}
+
+// inspectLocalRepo checks that dir can be published: it must be a
+// directory, and if it is a git repository it must be the top level, be on
+// a branch, and not have an origin remote yet.
+func inspectLocalRepo(dir string) (localRepo, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return localRepo{}, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return localRepo{}, fmt.Errorf("%s is not a directory", dir)
+	}
+	top, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return localRepo{}, nil
+	}
+	abs, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return localRepo{}, fmt.Errorf("resolving %s: %w", dir, err)
+	}
+	if top, err = filepath.EvalSymlinks(top); err != nil {
+		return localRepo{}, fmt.Errorf("resolving %s: %w", dir, err)
+	}
+	if abs != top {
+		return localRepo{}, fmt.Errorf("%s is inside the git repository %s", dir, top)
+	}
+	branch, err := runGit(dir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return localRepo{}, fmt.Errorf("%s has a detached HEAD; check out a branch to publish", dir)
+	}
+	remotes, err := runGit(dir, "remote")
+	if err != nil {
+		return localRepo{}, err
+	}
+	for _, r := range strings.Fields(remotes) {
+		if r == "origin" {
+			return localRepo{}, fmt.Errorf("%s already has an origin remote", dir)
+		}
+	}
+	return localRepo{exists: true, branch: branch}, nil
+}
+
+// initLocalRepo makes dir a git repository on branch (git's default when
+// empty) and commits whatever is already there. The boilerplate in bp is
+// written first, so a profile's .gitignore keeps ignored files out of the
+// commit. It returns the boilerplate files it wrote, for undoInitLocalRepo.
+func initLocalRepo(dir, branch string, bp config.BoilerplateConfig) ([]string, error) {
+	args := []string{"init"}
+	if branch != "" {
+		args = append(args, "--initial-branch", branch)
+	}
+	if _, err := runGit(dir, args...); err != nil {
+		return nil, err
+	}
+	written, err := scaffold.PrepareBoilerplate(bp, dir, userTemplateDir())
+	if err != nil {
+		return written, fmt.Errorf("preparing boilerplate: %w", err)
+	}
+	status, err := runGit(dir, "status", "--porcelain")
+	if err != nil || status == "" {
+		return written, err
+	}
+	if _, err := runGit(dir, "add", "-A"); err != nil {
+		return written, err
+	}
+	_, err = runGit(dir, "commit", "-m", "Initial commit")
+	return written, err
+}
+
+// undoInitLocalRepo puts dir back as it was before initLocalRepo: it
+// removes the repository and the boilerplate files written into dir.
+func undoInitLocalRepo(dir string, written []string) error {
+	for _, f := range written {
+		if err := os.Remove(filepath.Join(dir, f)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		// Drop the directories made for it, stopping at one that isn't
+		// empty.
+		for d := filepath.Dir(f); d != "."; d = filepath.Dir(d) {
+			if os.Remove(filepath.Join(dir, d)) != nil {
+				break
+			}
+		}
+	}
+	return os.RemoveAll(filepath.Join(dir, ".git"))
+}
+
+// missingBoilerplate returns the boilerplate files that don't exist in dir
+// yet. A project's own files always win over the profile's.
+func missingBoilerplate(bp config.BoilerplateConfig, dir string) config.BoilerplateConfig {
+	missing := bp
+	missing.Files = nil
+	for _, f := range bp.Files {
+		if _, err := os.Stat(filepath.Join(dir, f.Dest)); errors.Is(err, os.ErrNotExist) {
+			missing.Files = append(missing.Files, f)
+		}
+	}
+	return missing
+}
+
+// commitBoilerplate writes the boilerplate into dir and commits only those
+// files, leaving any other changes in the working tree alone.
+func commitBoilerplate(dir string, bp config.BoilerplateConfig) error {
+	written, err := scaffold.PrepareBoilerplate(bp, dir, userTemplateDir())
+	if err != nil {
+		return fmt.Errorf("preparing boilerplate: %w", err)
+	}
+	if _, err := runGit(dir, append([]string{"add", "--"}, written...)...); err != nil {
+		return err
+	}
+	_, err = runGit(dir, append([]string{"commit", "-m", "chore: add boilerplate files", "--"}, written...)...)
+	return err
+}
+
+// changedFiles returns the files in dir that differ from HEAD or are
+// untracked and not ignored.
+func changedFiles(dir string) (map[string]bool, error) {
+	lists := [][]string{{"ls-files", "-z", "--others", "--exclude-standard"}}
+	if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		lists = append(lists, []string{"diff", "-z", "--name-only", "HEAD"})
+	}
+	changed := map[string]bool{}
+	for _, args := range lists {
+		out, err := runGit(dir, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range strings.Split(out, "\x00") {
+			if f != "" {
+				changed[f] = true
+			}
+		}
+	}
+	return changed, nil
+}
+
+// commitHookChanges commits the files in dir that hook's commands changed,
+// given the files changedFiles found before they ran, leaving changes that
+// were already in the working tree alone. It returns the number of files
+// committed.
+func commitHookChanges(dir, hook string, before map[string]bool) (int, error) {
+	after, err := changedFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+	var files []string
+	for _, f := range slices.Sorted(maps.Keys(after)) {
+		if !before[f] {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return 0, nil
+	}
+	if _, err := runGit(dir, append([]string{"add", "-A", "--"}, files...)...); err != nil {
+		return 0, err
+	}
+	_, err = runGit(dir, append([]string{"commit", "-m", "chore: run " + hook + " hooks", "--"}, files...)...)
+	return len(files), err
+}
+
+// runHooksAndCommit runs a hook's commands in dir, like runHooks, then
+// commits the files they changed.
+func runHooksAndCommit(hook string, cmds [][]string, dir string, env []string, report func(name, output string, err error)) error {
+	before, err := changedFiles(dir)
+	if err != nil {
+		return err
+	}
+	if err := runHooks(hook, cmds, dir, env, report); err != nil {
+		return err
+	}
+	n, err := commitHookChanges(dir, hook, before)
+	if n > 0 || err != nil {
+		report(fmt.Sprintf("Committed %s changes (%d)", hook, n), "", err)
+	}
+	return err
+}
+
+// pushLocalHistory pushes the checked-out branch first, so it becomes the
+// default branch, then every other branch and tag.
+func pushLocalHistory(dir string) error {
+	if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return fmt.Errorf("nothing to push: %s has no commits", dir)
+	}
+	for _, args := range [][]string{
+		{"push", "--set-upstream", "origin", "HEAD"},
+		{"push", "origin", "--all"},
+		{"push", "origin", "--tags"},
+	} {
+		if _, err := runGit(dir, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishRepoArgs creates a repo with dir as its source, adding it to dir
+// as the origin remote without pushing.
+func (c *Client) publishRepoArgs(name, dir, description string, public bool) []string {
+	args := c.createRepoArgs(name, description, public)
+	return append(args, "--source", dir, "--remote", "origin")
+}
+
+// PublishRepo creates an empty repo for the git repository in dir and adds
+// it as dir's origin remote.
+func (c *Client) PublishRepo(name, dir, description string, public bool) (string, error) {
+	out, err := c.run(c.publishRepoArgs(name, dir, description, public)...)
+	if err != nil {
+		return "", fmt.Errorf("creating repo: %w", err)
+	}
+	return out, nil
+}
+
+// PublishWithDefaults publishes the local project in dir as a new repo
+// named opts.Name. It runs git init first if dir isn't a repository yet,
+// committing the boilerplate files dir doesn't have along with its
+// contents, and undoes that if the repo can't be created. It then creates
+// the repo with dir as its origin, and runs the steps of
+// CreateWithDefaults, except that in place of a boilerplate push it commits
+// the boilerplate files dir doesn't already have and pushes dir's history.
+// pre_create hooks run in dir before anything is made, and post_create
+// hooks run in dir before the push, with what they change committed. The
+// profile's template_repo is ignored.
+func (c *Client) PublishWithDefaults(dir string, opts CreateOpts) (string, error) {
+	opts.Template, opts.Profile.TemplateRepo = "", ""
+
+	local, err := inspectLocalRepo(dir)
+	if err != nil {
+		return "", err
+	}
+	if err := c.checkCustomProperties(opts); err != nil {
+		return "", err
+	}
+	target, err := c.targetNWO(opts)
+	if err != nil {
+		return "", err
+	}
+	exists, err := c.RepoExists(target)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", &RepoExistsError{NWO: target}
+	}
+	if err := c.preCreate(opts, target, dir); err != nil {
+		return "", err
+	}
+
+	// A repository made here is removed again if the repo can't be
+	// created, so a retry starts from the same directory.
+	var undo func()
+	if !local.exists {
+		bp := missingBoilerplate(opts.Profile.EffectiveBoilerplate(), dir)
+		start := time.Now()
+		written, err := initLocalRepo(dir, opts.Profile.BranchProtection.Branch, bp)
+		undo = func() {
+			opts.report("Removed git repository", undoInitLocalRepo(dir, written), 0)
+		}
+		opts.report("Initialised git repository", err, time.Since(start))
+		if err != nil {
+			undo()
+			return "", err
+		}
+	}
+
+	start := time.Now()
+	url, err := c.PublishRepo(opts.nwo(), dir, opts.Description, opts.Public)
+	opts.report("Created repository", err, time.Since(start))
+	if err != nil {
+		if undo != nil {
+			undo()
+		}
+		return "", err
+	}
+	nwo := target
+	if parts := splitRepoURL(url); parts != "" {
+		nwo = parts
+	}
+
+	run := &pipelineRun{c: c, opts: &opts, nwo: nwo, dir: dir}
+	errs, skipped := run.execute(profileSteps(&opts, modePublish), opts.Concurrency)
+	if len(errs) > 0 {
+		if skipped > 0 {
+			return url, fmt.Errorf("%d step(s) failed after publishing, %d skipped", len(errs), skipped)
+		}
+		return url, fmt.Errorf("%d step(s) failed after publishing", len(errs))
+	}
+	return url, nil
+}
@@ -0,0 +1,240 @@
+package github
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// gitTestDir returns an empty directory for tests that run git, with a
+// commit identity that doesn't depend on the user's config.
+func gitTestDir(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	return t.TempDir()
+}
+
+func TestPublishRepoArgs(t *testing.T) {
+	c := &Client{}
+	got := c.publishRepoArgs("acme/widget", "/src/widget", "A widget", false)
+	want := []string{"repo", "create", "acme/widget", "--private", "--description", "A widget", "--source", "/src/widget", "--remote", "origin"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %v, want %v", got, want)
+	}
+}
+
+func TestInspectLocalRepo(t *testing.T) {
+	dir := gitTestDir(t)
+
+	local, err := inspectLocalRepo(dir)
+	if err != nil || local.exists {
+		t.Fatalf("plain dir: %+v, %v", local, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := initLocalRepo(dir, "trunk", config.BoilerplateConfig{}); err != nil {
+		t.Fatalf("initLocalRepo: %v", err)
+	}
+	if out, err := runGit(dir, "log", "--format=%s"); err != nil || out != "Initial commit" {
+		t.Errorf("log = %q, %v", out, err)
+	}
+	local, err = inspectLocalRepo(dir)
+	if err != nil || !local.exists || local.branch != "trunk" {
+		t.Errorf("repo: %+v, %v", local, err)
+	}
+
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inspectLocalRepo(sub); err == nil || !strings.Contains(err.Error(), "inside the git repository") {
+		t.Errorf("subdirectory: err = %v", err)
+	}
+
+	if _, err := runGit(dir, "remote", "add", "origin", "https://example.com/x.git"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inspectLocalRepo(dir); err == nil || !strings.Contains(err.Error(), "origin remote") {
+		t.Errorf("existing origin: err = %v", err)
+	}
+
+	if _, err := inspectLocalRepo(filepath.Join(dir, "main.go")); err == nil {
+		t.Error("expected error for a file")
+	}
+}
+
+func TestInitLocalRepo_Empty(t *testing.T) {
+	dir := gitTestDir(t)
+	if _, err := initLocalRepo(dir, "", config.BoilerplateConfig{}); err != nil {
+		t.Fatalf("initLocalRepo: %v", err)
+	}
+	if err := pushLocalHistory(dir); err == nil || !strings.Contains(err.Error(), "no commits") {
+		t.Errorf("push with no commits: err = %v", err)
+	}
+}
+
+func TestInitLocalRepo_BoilerplateFirst(t *testing.T) {
+	dir := gitTestDir(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tmplDir := filepath.Join(home, ".config", "gh-mint", "templates")
+	if err := os.MkdirAll(tmplDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmplDir, "gitignore"), []byte(".env\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"main.go": "package main\n", ".env": "TOKEN=x\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bp := config.BoilerplateConfig{Files: []config.BoilerplateFile{
+		{Src: "gitignore", Dest: ".gitignore"},
+		{Src: "pages.yml", Dest: ".github/workflows/pages.yml"},
+	}}
+	written, err := initLocalRepo(dir, "main", bp)
+	if err != nil {
+		t.Fatalf("initLocalRepo: %v", err)
+	}
+	files, err := runGit(dir, "ls-files")
+	if err != nil || files != ".github/workflows/pages.yml\n.gitignore\nmain.go" {
+		t.Errorf("committed %q, %v; want .env ignored", files, err)
+	}
+
+	if err := undoInitLocalRepo(dir, written); err != nil {
+		t.Fatalf("undoInitLocalRepo: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, e := range entries {
+		left = append(left, e.Name())
+	}
+	if !reflect.DeepEqual(left, []string{".env", "main.go"}) {
+		t.Errorf("after undo dir has %v, want only the project's own files", left)
+	}
+}
+
+func TestMissingBoilerplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bp := config.BoilerplateConfig{License: "MIT", Files: []config.BoilerplateFile{
+		{Src: "LICENSE-MIT", Dest: "LICENSE"},
+		{Src: "editorconfig", Dest: ".editorconfig"},
+	}}
+	got := missingBoilerplate(bp, dir)
+	if len(got.Files) != 1 || got.Files[0].Dest != ".editorconfig" {
+		t.Errorf("files = %+v, want only .editorconfig", got.Files)
+	}
+	if got.License != "MIT" || len(bp.Files) != 2 {
+		t.Errorf("missingBoilerplate changed its input or dropped fields: %+v", got)
+	}
+}
+
+func TestCommitBoilerplate_LeavesOtherChanges(t *testing.T) {
+	dir := gitTestDir(t)
+	t.Setenv("HOME", t.TempDir())
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := initLocalRepo(dir, "main", config.BoilerplateConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bp := config.BoilerplateConfig{Files: []config.BoilerplateFile{{Src: "pages.yml", Dest: ".github/workflows/pages.yml"}}}
+	if err := commitBoilerplate(dir, bp); err != nil {
+		t.Fatalf("commitBoilerplate: %v", err)
+	}
+	files, err := runGit(dir, "show", "--name-only", "--format=", "HEAD")
+	if err != nil || files != ".github/workflows/pages.yml" {
+		t.Errorf("committed %q, %v", files, err)
+	}
+	status, err := runGit(dir, "status", "--porcelain")
+	if err != nil || status != "M main.go" {
+		t.Errorf("status = %q, %v; want main.go still modified", status, err)
+	}
+}
+
+func TestRunHooksAndCommit_LeavesOtherChanges(t *testing.T) {
+	dir := gitTestDir(t)
+	t.Setenv("HOME", t.TempDir())
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := initLocalRepo(dir, "main", config.BoilerplateConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var ran []string
+	cmds := [][]string{{"sh", "-c", "mkdir gen && echo x > gen/x.go && echo '// more' >> main.go"}}
+	err := runHooksAndCommit("post_create", cmds, dir, nil, func(name, output string, err error) {
+		ran = append(ran, name)
+	})
+	if err != nil {
+		t.Fatalf("runHooksAndCommit: %v", err)
+	}
+	want := []string{"Ran post_create: sh -c mkdir gen && echo x > gen/x.go && echo '// more' >> main.go", "Committed post_create changes (1)"}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("reported %q, want %q", ran, want)
+	}
+	files, err := runGit(dir, "show", "--name-only", "--format=", "HEAD")
+	if err != nil || files != "gen/x.go" {
+		t.Errorf("committed %q, %v; want only the hook's new file", files, err)
+	}
+	status, err := runGit(dir, "status", "--porcelain")
+	if err != nil || status != "M main.go" {
+		t.Errorf("status = %q, %v; want main.go still modified", status, err)
+	}
+
+	// A hook that changes nothing commits nothing.
+	ran = nil
+	if err := runHooksAndCommit("post_create", [][]string{{"true"}}, dir, nil, func(name, output string, err error) {
+		ran = append(ran, name)
+	}); err != nil || len(ran) != 1 {
+		t.Errorf("reported %q, %v; want only the command", ran, err)
+	}
+}
+
+func TestPublishPlan(t *testing.T) {
+	opts := CreateOpts{Template: "acme/go-template", Profile: config.Profile{
+		Seed:             config.SeedConfig{Issues: []config.SeedIssue{{Title: "Roadmap"}}},
+		BranchProtection: config.BranchProtection{Branch: "main"},
+	}}
+	plan := PublishPlan(opts)
+	want := []string{"settings", "security", "labels", "seed", "actions-policy", "push", "branch-protection"}
+	if got := planIDs(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("publish plan = %v, want %v", got, want)
+	}
+	for _, s := range plan {
+		if s.ID == "branch-protection" && !reflect.DeepEqual(s.DependsOn, []string{"push"}) {
+			t.Errorf("branch-protection depends on %v, want [push]", s.DependsOn)
+		}
+	}
+}
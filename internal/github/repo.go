@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/ggfevans/gh-mint/internal/config"
 )
@@ -17,6 +19,77 @@ func (c *Client) createRepoArgs(name, description string, public bool) []string
 	return []string{"repo", "create", name, visibility, "--description", description}
 }
 
+// RepoExistsError is returned by CreateWithDefaults when the repo it would
+// create already exists and CreateOpts.Adopt is unset.
+type RepoExistsError struct {
+	NWO string
+}
+
+func (e *RepoExistsError) Error() string {
+	return fmt.Sprintf("repository %s already exists", e.NWO)
+}
+
+// RepoExists reports whether nwo exists and is visible to the user.
+func (c *Client) RepoExists(nwo string) (bool, error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return false, fmt.Errorf("invalid nwo: %w", err)
+	}
+	if _, err := c.api("GET", "repos/"+nwo, nil); err != nil {
+		if strings.Contains(err.Error(), "HTTP 404") {
+			return false, nil
+		}
+		return false, fmt.Errorf("checking for repository %s: %w", nwo, err)
+	}
+	return true, nil
+}
+
+// createFromTemplateArgs generates a repo from a template repository,
+// copying all of its branches.
+func (c *Client) createFromTemplateArgs(name, template, description string, public bool) []string {
+	args := c.createRepoArgs(name, description, public)
+	return append(args, "--template", template, "--include-all-branches")
+}
+
+// CreateRepoFromTemplate generates a repo from the template repository
+// template (owner/name). GitHub fills it in asynchronously; see
+// waitForCommit.
+func (c *Client) CreateRepoFromTemplate(name, template, description string, public bool) (string, error) {
+	if err := config.ValidateNWO(template); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	out, err := c.run(c.createFromTemplateArgs(name, template, description, public)...)
+	if err != nil {
+		return "", fmt.Errorf("creating repo from template %s: %w", template, err)
+	}
+	return out, nil
+}
+
+// copyPoll is how often, and how many times, waitForCommit checks a
+// generated repo or fork for its first commit, and EnableWorkflow checks
+// for a pushed workflow.
+var copyPoll = struct {
+	interval time.Duration
+	attempts int
+}{2 * time.Second, 30}
+
+// waitForCommit waits until a repo generated from a template, or a fork,
+// has its contents, and returns the newest commit's SHA.
+func (c *Client) waitForCommit(nwo string) (string, error) {
+	for i := 1; ; i++ {
+		commits, err := c.listCommitSHAs(nwo)
+		if err != nil {
+			return "", err
+		}
+		if len(commits) > 0 {
+			return commits[0], nil
+		}
+		if i >= copyPoll.attempts {
+			return "", fmt.Errorf("repository %s is still empty; GitHub hasn't finished copying it", nwo)
+		}
+		time.Sleep(copyPoll.interval)
+	}
+}
+
 func (c *Client) CreateRepo(name, description string, public bool) (string, error) {
 	args := c.createRepoArgs(name, description, public)
 	out, err := c.run(args...)
@@ -41,3 +114,30 @@ func (c *Client) UpdateSettings(nwo string, settings map[string]interface{}) err
 	}
 	return nil
 }
+
+// SetHomepage renders a homepage template for the repo and sets it.
+func (c *Client) SetHomepage(nwo string, tmpl string) error {
+	homepage, err := config.RenderTemplate(tmpl, config.NewRepoVars(nwo))
+	if err != nil {
+		return fmt.Errorf("setting homepage: %w", err)
+	}
+	if err := config.ValidateHomepage(homepage); err != nil {
+		return fmt.Errorf("setting homepage: %w", err)
+	}
+	return c.UpdateSettings(nwo, map[string]interface{}{"homepage": homepage})
+}
+
+// DefaultBranch returns the repo's default branch.
+func (c *Client) DefaultBranch(nwo string) (string, error) {
+	out, err := c.api("GET", fmt.Sprintf("repos/%s", nwo), nil)
+	if err != nil {
+		return "", fmt.Errorf("getting default branch: %w", err)
+	}
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.Unmarshal([]byte(out), &repo); err != nil {
+		return "", fmt.Errorf("parsing repo: %w", err)
+	}
+	return repo.DefaultBranch, nil
+}
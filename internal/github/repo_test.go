@@ -1,8 +1,10 @@
 package github
 
 import (
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCreateRepoArgs(t *testing.T) {
@@ -28,3 +30,60 @@ func TestCreateRepoArgs_Private(t *testing.T) {
 		t.Errorf("missing --private flag: %v", args)
 	}
 }
+
+func TestRepoExists(t *testing.T) {
+	tests := []struct {
+		status  int
+		want    bool
+		wantErr bool
+	}{
+		{http.StatusOK, true, false},
+		{http.StatusNotFound, false, false},
+		{http.StatusInternalServerError, false, true},
+	}
+	for _, tt := range tests {
+		c, _ := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+			return tt.status, `{}`
+		})
+		got, err := c.RepoExists("acme/widget")
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("status %d: RepoExists = %v, %v; want %v, error %v", tt.status, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCreateFromTemplateArgs(t *testing.T) {
+	c := NewClient()
+	args := c.createFromTemplateArgs("acme/widget", "acme/go-template", "A widget", false)
+	joined := strings.Join(args, " ")
+	for _, want := range []string{"repo create acme/widget", "--private", "--template acme/go-template", "--include-all-branches"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in args: %v", want, args)
+		}
+	}
+}
+
+func TestWaitForCommit(t *testing.T) {
+	saved := copyPoll
+	copyPoll.interval = time.Millisecond
+	t.Cleanup(func() { copyPoll = saved })
+
+	calls := 0
+	c, _ := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		calls++
+		if calls < 3 {
+			return http.StatusConflict, `{"message": "Git Repository is empty."}`
+		}
+		return http.StatusOK, `[{"sha": "tpl"}]`
+	})
+	sha, err := c.waitForCommit("acme/widget")
+	if err != nil || sha != "tpl" {
+		t.Errorf("waitForCommit = %q, %v; want tpl", sha, err)
+	}
+
+	copyPoll.attempts = 2
+	calls = 0
+	if _, err := c.waitForCommit("acme/widget"); err == nil {
+		t.Error("expected an error while the repo is still empty")
+	}
+}
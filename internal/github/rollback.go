@@ -0,0 +1,114 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// parseScopesHeader finds X-OAuth-Scopes in `gh api -i` output. It reports
+// false when the header is absent, as for fine-grained tokens and apps.
+func parseScopesHeader(out string) ([]string, bool) {
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(name, "X-OAuth-Scopes") {
+			continue
+		}
+		var scopes []string
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes, true
+	}
+	return nil, false
+}
+
+// CheckDeleteScope confirms the token can delete repos. Tokens that don't
+// report OAuth scopes can't be checked and are refused.
+func (c *Client) CheckDeleteScope() error {
+	out, err := c.run("api", "-i", "user")
+	if err != nil {
+		return fmt.Errorf("checking token scopes: %w", err)
+	}
+	scopes, ok := parseScopesHeader(out)
+	if !ok {
+		return &AuthError{fmt.Errorf("token scopes can't be determined; rollback needs a classic token with delete_repo")}
+	}
+	if !slices.Contains(scopes, "delete_repo") {
+		return &AuthError{fmt.Errorf("token lacks the delete_repo scope (run: gh auth refresh -s delete_repo)")}
+	}
+	return nil
+}
+
+// rollbackSafe reports whether the repo's commits, newest first, are only
+// those gh-mint made. ours lists them newest first, such as the scaffold
+// commit and then the template's initial commit; empty entries are ignored.
+// commits may be missing the newer of them, if a step failed before making
+// it.
+func rollbackSafe(commits []string, ours ...string) error {
+	var want []string
+	for _, sha := range ours {
+		if sha != "" {
+			want = append(want, sha)
+		}
+	}
+	if len(commits) <= len(want) && slices.Equal(commits, want[len(want)-len(commits):]) {
+		return nil
+	}
+	return fmt.Errorf("repository has commits gh-mint didn't push; not deleting it")
+}
+
+// listCommitSHAs returns up to two commit SHAs on the default branch. An
+// empty repo answers 409.
+func (c *Client) listCommitSHAs(nwo string) ([]string, error) {
+	out, err := c.api("GET", fmt.Sprintf("repos/%s/commits?per_page=2", nwo), nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "HTTP 409") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing commits: %w", err)
+	}
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.Unmarshal([]byte(out), &commits); err != nil {
+		return nil, fmt.Errorf("parsing commits: %w", err)
+	}
+	shas := make([]string, len(commits))
+	for i, cm := range commits {
+		shas[i] = cm.SHA
+	}
+	return shas, nil
+}
+
+// RollbackRepo deletes a repo created by this run, after confirming the
+// token may delete it and it holds no commits beyond ours: the commits this
+// run made, newest first.
+func (c *Client) RollbackRepo(nwo string, ours ...string) error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return fmt.Errorf("invalid nwo: %w", err)
+	}
+	if err := c.CheckDeleteScope(); err != nil {
+		return err
+	}
+	commits, err := c.listCommitSHAs(nwo)
+	if err != nil {
+		return err
+	}
+	if err := rollbackSafe(commits, ours...); err != nil {
+		return err
+	}
+	if _, err := c.api("DELETE", "repos/"+nwo, nil); err != nil {
+		return fmt.Errorf("deleting repository: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseScopesHeader(t *testing.T) {
+	out := "HTTP/2.0 200 OK\nContent-Type: application/json\nX-Oauth-Scopes: delete_repo, gist, repo\nX-Github-Media-Type: github.v3\n\n{\"login\": \"x-oauth-scopes: admin\"}"
+	scopes, ok := parseScopesHeader(out)
+	if !ok || !reflect.DeepEqual(scopes, []string{"delete_repo", "gist", "repo"}) {
+		t.Errorf("parseScopesHeader = %v, %v", scopes, ok)
+	}
+
+	if _, ok := parseScopesHeader("HTTP/2.0 200 OK\nContent-Type: application/json\n\n{}"); ok {
+		t.Error("expected no scopes header for a fine-grained token")
+	}
+	if scopes, ok := parseScopesHeader("HTTP/2.0 200 OK\nX-OAuth-Scopes: \n\n{}"); !ok || len(scopes) != 0 {
+		t.Errorf("empty header = %v, %v", scopes, ok)
+	}
+}
+
+func TestRollbackSafe(t *testing.T) {
+	tests := []struct {
+		name     string
+		commits  []string
+		scaffold string
+		template string
+		wantErr  bool
+	}{
+		{"empty repo", nil, "", "", false},
+		{"only scaffold", []string{"abc"}, "abc", "", false},
+		{"unknown commit", []string{"def"}, "abc", "", true},
+		{"commit without scaffold", []string{"def"}, "", "", true},
+		{"scaffold plus more", []string{"def", "abc"}, "abc", "", true},
+		{"only template", []string{"tpl"}, "", "tpl", false},
+		{"template before scaffold push", []string{"tpl"}, "abc", "tpl", false},
+		{"scaffold on template", []string{"abc", "tpl"}, "abc", "tpl", false},
+		{"push on template", []string{"def", "tpl"}, "", "tpl", true},
+		{"scaffold on other commit", []string{"abc", "def"}, "abc", "tpl", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rollbackSafe(tt.commits, tt.scaffold, tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("rollbackSafe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListCommitSHAs_EmptyRepo(t *testing.T) {
+	c, _ := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		return http.StatusConflict, `{"message": "Git Repository is empty."}`
+	})
+	shas, err := c.listCommitSHAs("acme/widget")
+	if err != nil || len(shas) != 0 {
+		t.Errorf("listCommitSHAs = %v, %v", shas, err)
+	}
+}
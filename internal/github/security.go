@@ -0,0 +1,98 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// securityToggle is a single security feature. Features with an endpoint are
+// switched with PUT/DELETE; the rest are fields under security_and_analysis
+// on the repo PATCH.
+type securityToggle struct {
+	label       string
+	enabled     bool
+	endpoint    string
+	analysisKey string
+}
+
+func (t securityToggle) stepName() string {
+	if t.enabled {
+		return "Enabled " + t.label
+	}
+	return "Disabled " + t.label
+}
+
+// securityToggles returns the configured toggles in the order they must be
+// applied: alerts before automated fixes, scanning before push protection.
+func securityToggles(sec config.SecurityConfig) []securityToggle {
+	candidates := []struct {
+		value *bool
+		securityToggle
+	}{
+		{sec.VulnerabilityAlerts, securityToggle{label: "vulnerability alerts", endpoint: "vulnerability-alerts"}},
+		{sec.AutomatedSecurityFixes, securityToggle{label: "Dependabot security updates", endpoint: "automated-security-fixes"}},
+		{sec.SecretScanning, securityToggle{label: "secret scanning", analysisKey: "secret_scanning"}},
+		{sec.SecretScanningPushProtection, securityToggle{label: "secret scanning push protection", analysisKey: "secret_scanning_push_protection"}},
+		{sec.PrivateVulnerabilityReporting, securityToggle{label: "private vulnerability reporting", endpoint: "private-vulnerability-reporting"}},
+	}
+
+	var toggles []securityToggle
+	for _, c := range candidates {
+		if c.value == nil {
+			continue
+		}
+		t := c.securityToggle
+		t.enabled = *c.value
+		toggles = append(toggles, t)
+	}
+	return toggles
+}
+
+// securityRequest returns the method, endpoint, and body for a toggle.
+func securityRequest(nwo string, t securityToggle) (string, string, interface{}) {
+	if t.analysisKey != "" {
+		status := "disabled"
+		if t.enabled {
+			status = "enabled"
+		}
+		body := map[string]interface{}{
+			"security_and_analysis": map[string]interface{}{
+				t.analysisKey: map[string]string{"status": status},
+			},
+		}
+		return "PATCH", fmt.Sprintf("repos/%s", nwo), body
+	}
+	method := "DELETE"
+	if t.enabled {
+		method = "PUT"
+	}
+	return method, fmt.Sprintf("repos/%s/%s", nwo, t.endpoint), nil
+}
+
+func (c *Client) setSecurityToggle(nwo string, t securityToggle) error {
+	method, endpoint, body := securityRequest(nwo, t)
+	if _, err := c.api(method, endpoint, body); err != nil {
+		return fmt.Errorf("setting %s: %w", t.label, err)
+	}
+	return nil
+}
+
+// ApplySecurity applies each configured security feature as its own step,
+// calling report after each one. Returns the errors of failed steps.
+func (c *Client) ApplySecurity(nwo string, sec config.SecurityConfig, report func(name string, err error)) []error {
+	if err := config.ValidateNWO(nwo); err != nil {
+		err = fmt.Errorf("invalid nwo: %w", err)
+		report("Applied security settings", err)
+		return []error{err}
+	}
+	var errs []error
+	for _, t := range securityToggles(sec) {
+		err := c.setSecurityToggle(nwo, t)
+		report(t.stepName(), err)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,66 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestSecurityToggles_SkipsUnset(t *testing.T) {
+	tr := true
+	toggles := securityToggles(config.SecurityConfig{SecretScanning: &tr})
+	if len(toggles) != 1 {
+		t.Fatalf("expected 1 toggle, got %d", len(toggles))
+	}
+	if toggles[0].stepName() != "Enabled secret scanning" {
+		t.Errorf("step name = %q", toggles[0].stepName())
+	}
+}
+
+func TestSecurityToggles_Order(t *testing.T) {
+	tr := true
+	sec := config.SecurityConfig{
+		PrivateVulnerabilityReporting: &tr,
+		AutomatedSecurityFixes:        &tr,
+		VulnerabilityAlerts:           &tr,
+	}
+	toggles := securityToggles(sec)
+	want := []string{"vulnerability-alerts", "automated-security-fixes", "private-vulnerability-reporting"}
+	if len(toggles) != len(want) {
+		t.Fatalf("expected %d toggles, got %d", len(want), len(toggles))
+	}
+	for i, w := range want {
+		if toggles[i].endpoint != w {
+			t.Errorf("toggle %d endpoint = %q, want %q", i, toggles[i].endpoint, w)
+		}
+	}
+}
+
+func TestSecurityRequest_Endpoint(t *testing.T) {
+	f := false
+	toggles := securityToggles(config.SecurityConfig{VulnerabilityAlerts: &f})
+	method, endpoint, body := securityRequest("owner/repo", toggles[0])
+	if method != "DELETE" {
+		t.Errorf("method = %q, want DELETE", method)
+	}
+	if endpoint != "repos/owner/repo/vulnerability-alerts" {
+		t.Errorf("endpoint = %q", endpoint)
+	}
+	if body != nil {
+		t.Errorf("expected no body, got %v", body)
+	}
+}
+
+func TestSecurityRequest_Analysis(t *testing.T) {
+	tr := true
+	toggles := securityToggles(config.SecurityConfig{SecretScanningPushProtection: &tr})
+	method, endpoint, body := securityRequest("owner/repo", toggles[0])
+	if method != "PATCH" || endpoint != "repos/owner/repo" {
+		t.Errorf("got %s %s, want PATCH repos/owner/repo", method, endpoint)
+	}
+	saa := body.(map[string]interface{})["security_and_analysis"].(map[string]interface{})
+	status := saa["secret_scanning_push_protection"].(map[string]string)["status"]
+	if status != "enabled" {
+		t.Errorf("status = %q, want enabled", status)
+	}
+}
@@ -0,0 +1,209 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// SeededIssue is an issue opened from the profile's seed section.
+type SeededIssue struct {
+	Number int    `json:"number"`
+	NodeID string `json:"node_id"`
+	Title  string `json:"title"`
+	Pinned bool   `json:"pinned,omitempty"`
+}
+
+// milestonePayload builds the milestone POST body. now is passed in so due
+// dates are testable.
+func milestonePayload(m config.SeedMilestone, now time.Time) (map[string]interface{}, error) {
+	body := map[string]interface{}{"title": m.Title}
+	if m.Description != "" {
+		body["description"] = m.Description
+	}
+	if m.DueIn != "" {
+		d, err := config.ParseDueIn(m.DueIn)
+		if err != nil {
+			return nil, err
+		}
+		due := now.UTC().Add(d)
+		body["due_on"] = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	return body, nil
+}
+
+func issuePayload(i config.SeedIssue, nwo string, milestones map[string]int) (map[string]interface{}, error) {
+	body := map[string]interface{}{"title": i.Title}
+	if i.Body != "" {
+		text, err := config.RenderTemplate(i.Body, config.NewRepoVars(nwo))
+		if err != nil {
+			return nil, err
+		}
+		body["body"] = text
+	}
+	if len(i.Labels) > 0 {
+		body["labels"] = i.Labels
+	}
+	if len(i.Assignees) > 0 {
+		body["assignees"] = i.Assignees
+	}
+	if i.Milestone != "" {
+		number, ok := milestones[i.Milestone]
+		if !ok {
+			return nil, fmt.Errorf("milestone %q was not created", i.Milestone)
+		}
+		body["milestone"] = number
+	}
+	return body, nil
+}
+
+// CreateMilestones creates the seed milestones and returns their numbers
+// by title.
+func (c *Client) CreateMilestones(nwo string, milestones []config.SeedMilestone) (map[string]int, []error) {
+	numbers := make(map[string]int)
+	if err := config.ValidateNWO(nwo); err != nil {
+		return numbers, []error{fmt.Errorf("invalid nwo: %w", err)}
+	}
+	var errs []error
+	for _, m := range milestones {
+		body, err := milestonePayload(m, time.Now())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("milestone %q: %w", m.Title, err))
+			continue
+		}
+		out, err := c.api("POST", fmt.Sprintf("repos/%s/milestones", nwo), body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("creating milestone %q: %w", m.Title, err))
+			continue
+		}
+		var resp struct {
+			Number int `json:"number"`
+		}
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			errs = append(errs, fmt.Errorf("parsing milestone %q: %w", m.Title, err))
+			continue
+		}
+		numbers[m.Title] = resp.Number
+	}
+	return numbers, errs
+}
+
+const pinIssueMutation = `mutation($id: ID!) { pinIssue(input: {issueId: $id}) { issue { number } } }`
+
+// SeedIssues opens the seed issues, attaching milestones by number, and
+// pins those marked pin.
+func (c *Client) SeedIssues(nwo string, issues []config.SeedIssue, milestones map[string]int) ([]SeededIssue, []error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return nil, []error{fmt.Errorf("invalid nwo: %w", err)}
+	}
+	var seeded []SeededIssue
+	var errs []error
+	for _, i := range issues {
+		body, err := issuePayload(i, nwo, milestones)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("issue %q: %w", i.Title, err))
+			continue
+		}
+		out, err := c.api("POST", fmt.Sprintf("repos/%s/issues", nwo), body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("creating issue %q: %w", i.Title, err))
+			continue
+		}
+		var issue SeededIssue
+		if err := json.Unmarshal([]byte(out), &issue); err != nil {
+			errs = append(errs, fmt.Errorf("parsing issue %q: %w", i.Title, err))
+			continue
+		}
+		if i.Pin {
+			if err := c.pinIssue(issue); err != nil {
+				errs = append(errs, err)
+			} else {
+				issue.Pinned = true
+			}
+		}
+		seeded = append(seeded, issue)
+	}
+	return seeded, errs
+}
+
+// pinIssue pins an opened issue.
+func (c *Client) pinIssue(issue SeededIssue) error {
+	if err := c.graphql(pinIssueMutation, map[string]interface{}{"id": issue.NodeID}, nil); err != nil {
+		return fmt.Errorf("pinning issue #%d: %w", issue.Number, err)
+	}
+	return nil
+}
+
+// PinSeededIssues retries the pins that failed on an earlier run, marking
+// each issue it pins.
+func (c *Client) PinSeededIssues(issues []*SeededIssue) []error {
+	var errs []error
+	for _, issue := range issues {
+		if err := c.pinIssue(*issue); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		issue.Pinned = true
+	}
+	return errs
+}
+
+// seedProgress is what the seed step has created so far, kept in the run
+// journal so a retry only opens what is missing.
+type seedProgress struct {
+	Milestones map[string]int `json:"milestones,omitempty"`
+	Issues     []SeededIssue  `json:"issues,omitempty"`
+}
+
+func (s seedProgress) pendingMilestones(milestones []config.SeedMilestone) []config.SeedMilestone {
+	var pending []config.SeedMilestone
+	for _, m := range milestones {
+		if _, ok := s.Milestones[m.Title]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// pendingIssues matches by title, counting duplicates, so two seed issues
+// with the same title are both opened.
+func (s seedProgress) pendingIssues(issues []config.SeedIssue) []config.SeedIssue {
+	opened := make(map[string]int)
+	for _, i := range s.Issues {
+		opened[i.Title]++
+	}
+	var pending []config.SeedIssue
+	for _, i := range issues {
+		if opened[i.Title] > 0 {
+			opened[i.Title]--
+			continue
+		}
+		pending = append(pending, i)
+	}
+	return pending
+}
+
+// unpinned returns the opened issues whose seed issue is marked pin but
+// which aren't pinned yet, matching by title like pendingIssues.
+func (s seedProgress) unpinned(issues []config.SeedIssue) []*SeededIssue {
+	pin := make(map[string]int)
+	for _, i := range issues {
+		if i.Pin {
+			pin[i.Title]++
+		}
+	}
+	var pending []*SeededIssue
+	for n := range s.Issues {
+		issue := &s.Issues[n]
+		if pin[issue.Title] == 0 {
+			continue
+		}
+		pin[issue.Title]--
+		if !issue.Pinned {
+			pending = append(pending, issue)
+		}
+	}
+	return pending
+}
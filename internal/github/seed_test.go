@@ -0,0 +1,163 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestMilestonePayload_DueOn(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+	body, err := milestonePayload(config.SeedMilestone{Title: "v0.1", DueIn: "2w"}, now)
+	if err != nil {
+		t.Fatalf("milestonePayload: %v", err)
+	}
+	if body["due_on"] != "2025-03-24T00:00:00Z" {
+		t.Errorf("due_on = %v, want 2025-03-24T00:00:00Z", body["due_on"])
+	}
+	if _, ok := body["description"]; ok {
+		t.Error("description should be omitted when empty")
+	}
+}
+
+func TestIssuePayload(t *testing.T) {
+	issue := config.SeedIssue{Title: "Welcome", Body: "Thanks for looking at {{.Name}}", Labels: []string{"docs"}, Milestone: "v0.1"}
+	body, err := issuePayload(issue, "acme/widget", map[string]int{"v0.1": 3})
+	if err != nil {
+		t.Fatalf("issuePayload: %v", err)
+	}
+	if body["body"] != "Thanks for looking at widget" {
+		t.Errorf("body = %v", body["body"])
+	}
+	if body["milestone"] != 3 {
+		t.Errorf("milestone = %v, want 3", body["milestone"])
+	}
+	if _, ok := body["assignees"]; ok {
+		t.Error("assignees should be omitted when empty")
+	}
+
+	if _, err := issuePayload(issue, "acme/widget", nil); err == nil {
+		t.Error("expected error when the milestone was not created")
+	}
+}
+
+func TestSeedIssues_FakeEndpoint(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		switch r.Path {
+		case "/repos/acme/widget/milestones":
+			return http.StatusCreated, `{"number": 1}`
+		case "/repos/acme/widget/issues":
+			if strings.Contains(r.Body, "Roadmap") {
+				return http.StatusCreated, `{"number": 1, "node_id": "I_1", "title": "Roadmap"}`
+			}
+			return http.StatusCreated, `{"number": 2, "node_id": "I_2", "title": "Set up CI"}`
+		case "/graphql":
+			return http.StatusOK, `{"data": {"pinIssue": {"issue": {"number": 1}}}}`
+		}
+		return http.StatusNotFound, ""
+	})
+
+	milestones, errs := c.CreateMilestones("acme/widget", []config.SeedMilestone{{Title: "v0.1"}})
+	if len(errs) > 0 || milestones["v0.1"] != 1 {
+		t.Fatalf("CreateMilestones = %v, %v", milestones, errs)
+	}
+	seeded, errs := c.SeedIssues("acme/widget", []config.SeedIssue{
+		{Title: "Roadmap", Pin: true},
+		{Title: "Set up CI", Milestone: "v0.1"},
+	}, milestones)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(seeded) != 2 || seeded[0].NodeID != "I_1" || seeded[1].Number != 2 {
+		t.Errorf("seeded = %+v", seeded)
+	}
+
+	reqs := api.recorded()
+	if len(reqs) != 4 {
+		t.Fatalf("expected 4 requests, got %d: %+v", len(reqs), reqs)
+	}
+	if reqs[2].Path != "/graphql" {
+		t.Fatalf("pin request = %+v, want graphql after the pinned issue", reqs[2])
+	}
+	var pin struct {
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.Unmarshal([]byte(reqs[2].Body), &pin); err != nil || pin.Variables["id"] != "I_1" {
+		t.Errorf("pin body = %s", reqs[2].Body)
+	}
+	var second map[string]interface{}
+	if err := json.Unmarshal([]byte(reqs[3].Body), &second); err != nil || second["milestone"] != float64(1) {
+		t.Errorf("second issue body = %s", reqs[3].Body)
+	}
+}
+
+func TestGraphQL_Errors(t *testing.T) {
+	c, _ := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		return http.StatusOK, `{"data": null, "errors": [{"message": "Could not resolve to a node"}]}`
+	})
+	err := c.graphql(pinIssueMutation, map[string]interface{}{"id": "nope"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("graphql error = %v", err)
+	}
+}
+
+func TestSeedProgress_Pending(t *testing.T) {
+	progress := seedProgress{
+		Milestones: map[string]int{"v0.1": 1},
+		Issues:     []SeededIssue{{Number: 1, Title: "Set up CI"}},
+	}
+	ms := progress.pendingMilestones([]config.SeedMilestone{{Title: "v0.1"}, {Title: "v1.0"}})
+	if len(ms) != 1 || ms[0].Title != "v1.0" {
+		t.Errorf("pending milestones = %+v", ms)
+	}
+	issues := progress.pendingIssues([]config.SeedIssue{{Title: "Set up CI"}, {Title: "Set up CI"}, {Title: "Write README"}})
+	if len(issues) != 2 || issues[0].Title != "Set up CI" || issues[1].Title != "Write README" {
+		t.Errorf("pending issues = %+v", issues)
+	}
+}
+
+func TestSeedIssues_RetriesFailedPin(t *testing.T) {
+	pinFails := true
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		switch r.Path {
+		case "/repos/acme/widget/issues":
+			return http.StatusCreated, `{"number": 1, "node_id": "I_1", "title": "Roadmap"}`
+		case "/graphql":
+			if pinFails {
+				return http.StatusBadGateway, ""
+			}
+			return http.StatusOK, `{"data": {"pinIssue": {"issue": {"number": 1}}}}`
+		}
+		return http.StatusNotFound, ""
+	})
+
+	issues := []config.SeedIssue{{Title: "Roadmap", Pin: true}, {Title: "Write README"}}
+	seeded, errs := c.SeedIssues("acme/widget", issues[:1], nil)
+	if len(errs) != 1 || len(seeded) != 1 || seeded[0].Pinned {
+		t.Fatalf("SeedIssues = %+v, %v; want the issue opened but not pinned", seeded, errs)
+	}
+
+	progress := seedProgress{Issues: append(seeded, SeededIssue{Number: 2, Title: "Write README"})}
+	if pending := progress.pendingIssues(issues); len(pending) != 0 {
+		t.Errorf("pending issues = %+v, want none", pending)
+	}
+	unpinned := progress.unpinned(issues)
+	if len(unpinned) != 1 || unpinned[0].Number != 1 {
+		t.Fatalf("unpinned = %+v, want #1", unpinned)
+	}
+
+	pinFails = false
+	if errs := c.PinSeededIssues(unpinned); len(errs) > 0 {
+		t.Fatalf("PinSeededIssues: %v", errs)
+	}
+	if !progress.Issues[0].Pinned || len(progress.unpinned(issues)) != 0 {
+		t.Errorf("progress = %+v, want #1 recorded as pinned", progress)
+	}
+	if reqs := api.recorded(); reqs[len(reqs)-1].Path != "/graphql" {
+		t.Errorf("last request = %+v, want the pin retry", reqs[len(reqs)-1])
+	}
+}
@@ -0,0 +1,389 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// errList wraps a single error as a step's error list.
+func errList(err error) []error {
+	if err == nil {
+		return nil
+	}
+	return []error{err}
+}
+
+// countedErr folds a step's item errors into one error for its report.
+func countedErr(kind string, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d %s errors: %w", len(errs), kind, errs[0])
+}
+
+// pipelineMode says what kind of repo a pipeline configures.
+type pipelineMode int
+
+const (
+	modeApply   pipelineMode = iota // an existing repo
+	modeCreate                      // a repo this run created
+	modeFork                        // a fork this run made
+	modePublish                     // a repo this run created for a local project
+)
+
+// profileSteps declares the pipeline for a profile, in a valid run order.
+// Steps only appear when the profile configures them. Create adds the steps
+// that only make sense on a new repo (seeding, deploy keys, and
+// boilerplate); fork adds only the upstream-sync workflow; publish adds the
+// create steps but pushes the local history in place of the boilerplate.
+func profileSteps(opts *CreateOpts, mode pipelineMode) []pipelineStep {
+	p := opts.Profile
+	create := mode == modeCreate || mode == modePublish
+	var steps []pipelineStep
+	add := func(s pipelineStep) { steps = append(steps, s) }
+
+	add(pipelineStep{id: "settings", name: "Applied repo settings", run: func(r *stepRun) []error {
+		settings, err := SettingsFromRepoSettings(p.Settings)
+		if err == nil {
+			err = r.c.UpdateSettings(r.nwo, settings)
+		}
+		r.report("Applied repo settings", err)
+		return errList(err)
+	}})
+
+	// A generated repo is filled in asynchronously; steps that clone it or
+	// need its branches wait for its first commit.
+	if tmpl := opts.template(); mode == modeCreate && tmpl != "" {
+		add(pipelineStep{id: "contents", name: "Copied template contents", run: func(r *stepRun) []error {
+			sha, err := r.c.waitForCommit(r.nwo)
+			r.templateSHA = sha
+			r.report("Copied template contents from "+tmpl, err)
+			return errList(err)
+		}})
+	}
+	// So is a fork.
+	if mode == modeFork {
+		add(pipelineStep{id: "contents", name: "Copied upstream contents", run: func(r *stepRun) []error {
+			_, err := r.c.waitForCommit(r.nwo)
+			r.report("Copied upstream contents", err)
+			return errList(err)
+		}})
+	}
+
+	// Settings turn Discussions on before the categories can be read.
+	if cats := p.Discussions.Categories; len(cats) > 0 {
+		add(pipelineStep{id: "discussions", name: "Checked discussion categories", after: []string{"settings"}, run: func(r *stepRun) []error {
+			note, err := r.c.CheckDiscussionCategories(r.nwo, cats)
+			r.reportOutput("Checked discussion categories", note, err)
+			return errList(err)
+		}})
+	}
+
+	if len(p.CustomProperties) > 0 {
+		add(pipelineStep{id: "custom-properties", name: "Set custom properties", run: func(r *stepRun) []error {
+			err := r.c.SetCustomProperties(r.nwo, p.CustomProperties)
+			r.report("Set custom properties", err)
+			return errList(err)
+		}})
+	}
+
+	add(pipelineStep{id: "security", name: "Applied security features", run: func(r *stepRun) []error {
+		return r.c.ApplySecurity(r.nwo, p.Security, r.report)
+	}})
+
+	if len(p.Topics.Items) > 0 || len(opts.Topics) > 0 {
+		add(pipelineStep{id: "topics", name: "Set topics", run: func(r *stepRun) []error {
+			n, err := r.c.SyncTopics(r.nwo, p.Topics, opts.Topics)
+			r.report(fmt.Sprintf("Set topics (%d)", n), err)
+			return errList(err)
+		}})
+	}
+
+	if homepage := opts.homepage(); homepage != "" {
+		add(pipelineStep{id: "homepage", name: "Set homepage", run: func(r *stepRun) []error {
+			err := r.c.SetHomepage(r.nwo, homepage)
+			r.report("Set homepage", err)
+			return errList(err)
+		}})
+	}
+
+	// A retried label step reconciles instead, so labels that were already
+	// created are not deleted again.
+	add(pipelineStep{id: "labels", name: "Synced labels", run: func(r *stepRun) []error {
+		sync := r.c.SyncLabels
+		if r.attempted("labels") {
+			sync = r.c.ReconcileLabels
+		}
+		diff, labelErrs := sync(r.nwo, p.Labels)
+		if opts.OnLabelDiff != nil {
+			opts.OnLabelDiff(diff)
+		}
+		err := countedErr("label", labelErrs)
+		r.report(fmt.Sprintf("Synced labels (-%d/+%d)", len(diff.Deleted), len(diff.Created)), err)
+		return errList(err)
+	}})
+
+	if p.Access.IsSet() {
+		add(pipelineStep{id: "access", name: "Synced access", run: func(r *stepRun) []error {
+			granted, removed, accessErrs := r.c.SyncAccess(r.nwo, p.Access)
+			err := countedErr("access", accessErrs)
+			r.report(fmt.Sprintf("Synced access (+%d/-%d)", granted, removed), err)
+			return errList(err)
+		}})
+	}
+
+	// Seeded issues need issues enabled, their labels, and assignees with
+	// access. Progress is journaled so a retry skips what was opened.
+	if s := p.Seed; create && (len(s.Milestones) > 0 || len(s.Issues) > 0) {
+		add(pipelineStep{id: "seed", name: "Seeded issues", after: []string{"settings", "labels", "access"}, run: func(r *stepRun) []error {
+			if r.seed.Milestones == nil {
+				r.seed.Milestones = make(map[string]int)
+			}
+			var errs []error
+			if len(s.Milestones) > 0 {
+				milestones, milestoneErrs := r.c.CreateMilestones(r.nwo, r.seed.pendingMilestones(s.Milestones))
+				for title, number := range milestones {
+					r.seed.Milestones[title] = number
+				}
+				err := countedErr("milestone", milestoneErrs)
+				r.report(fmt.Sprintf("Created milestones (%d)", len(milestones)), err)
+				errs = append(errs, errList(err)...)
+			}
+			if unpinned := r.seed.unpinned(s.Issues); len(unpinned) > 0 {
+				pinErrs := r.c.PinSeededIssues(unpinned)
+				err := countedErr("pin", pinErrs)
+				r.report(fmt.Sprintf("Pinned issues (%d)", len(unpinned)-len(pinErrs)), err)
+				errs = append(errs, errList(err)...)
+			}
+			if len(s.Issues) > 0 {
+				issues, issueErrs := r.c.SeedIssues(r.nwo, r.seed.pendingIssues(s.Issues), r.seed.Milestones)
+				r.seed.Issues = append(r.seed.Issues, issues...)
+				err := countedErr("issue", issueErrs)
+				r.report(fmt.Sprintf("Seeded issues (%d)", len(issues)), err)
+				errs = append(errs, errList(err)...)
+			}
+			r.output("seed", r.seed)
+			return errs
+		}})
+	}
+
+	if cfg := p.Project; cfg.IsSet() {
+		add(pipelineStep{id: "project", name: "Linked project", after: []string{"seed"}, run: func(r *stepRun) []error {
+			proj, err := r.c.LinkProject(r.nwo, cfg)
+			name := "Linked project"
+			if proj.Title != "" {
+				name = fmt.Sprintf("Linked project %q", proj.Title)
+			}
+			r.report(name, err)
+			if err != nil {
+				return []error{err}
+			}
+			if !cfg.AddSeededIssues || len(r.seed.Issues) == 0 {
+				return nil
+			}
+			added, itemErrs := r.c.AddIssuesToProject(proj, cfg, r.seed.Issues)
+			err = countedErr("project item", itemErrs)
+			r.report(fmt.Sprintf("Added issues to project (%d)", added), err)
+			return errList(err)
+		}})
+	}
+
+	// A new fork has Actions off, so the upstream-sync workflow would never
+	// run unless the profile says otherwise.
+	actions := p.Actions
+	if mode == modeFork && p.Fork.SyncUpstream && actions.Enabled == nil {
+		enabled := true
+		actions.Enabled = &enabled
+	}
+	add(pipelineStep{id: "actions-policy", name: "Set Actions permissions", run: func(r *stepRun) []error {
+		return r.c.ApplyActionsPolicy(r.nwo, actions, r.report)
+	}})
+	if vars := p.Actions.Variables; len(vars) > 0 {
+		add(pipelineStep{id: "actions-variables", name: "Set Actions variables", run: func(r *stepRun) []error {
+			n, varErrs := r.c.SyncActionsVariables(r.nwo, vars)
+			err := countedErr("variable", varErrs)
+			r.report(fmt.Sprintf("Set Actions variables (%d)", n), err)
+			return errList(err)
+		}})
+	}
+	if srcs := p.Actions.Secrets; len(srcs) > 0 {
+		add(pipelineStep{id: "actions-secrets", name: "Set Actions secrets", run: func(r *stepRun) []error {
+			n, secretErrs := r.c.SyncActionsSecrets(r.nwo, srcs)
+			err := countedErr("secret", secretErrs)
+			r.report(fmt.Sprintf("Set Actions secrets (%d)", n), err)
+			return errList(err)
+		}})
+	}
+
+	if len(p.Autolinks) > 0 {
+		add(pipelineStep{id: "autolinks", name: "Synced autolinks", run: func(r *stepRun) []error {
+			created, replaced, linkErrs := r.c.SyncAutolinks(r.nwo, p.Autolinks)
+			err := countedErr("autolink", linkErrs)
+			r.report(fmt.Sprintf("Synced autolinks (+%d/~%d)", created, replaced), err)
+			return errList(err)
+		}})
+	}
+
+	if len(p.Webhooks) > 0 {
+		add(pipelineStep{id: "webhooks", name: "Synced webhooks", run: func(r *stepRun) []error {
+			created, updated, removed, hookErrs := r.c.SyncWebhooks(r.nwo, p.Webhooks)
+			err := countedErr("webhook", hookErrs)
+			r.report(fmt.Sprintf("Synced webhooks (+%d/~%d/-%d)", created, updated, removed), err)
+			return errList(err)
+		}})
+	}
+
+	// Keys added by an earlier attempt are skipped on retry.
+	if create && len(p.DeployKeys) > 0 {
+		add(pipelineStep{id: "deploy-keys", name: "Added deploy keys", run: func(r *stepRun) []error {
+			var done []string
+			r.savedOutput("deploy-keys", &done)
+			added, keyErrs := r.c.AddDeployKeys(r.nwo, pendingDeployKeys(p.DeployKeys, done), opts.OnPrivateKey)
+			done = append(done, added...)
+			r.output("deploy-keys", done)
+			r.mu.Lock()
+			r.keyFiles = privateKeyPaths(p.DeployKeys, done, config.NewRepoVars(r.nwo))
+			r.mu.Unlock()
+			err := countedErr("deploy key", keyErrs)
+			r.report(fmt.Sprintf("Added deploy keys (%d)", len(added)), err)
+			return errList(err)
+		}})
+	}
+
+	if len(p.Environments) > 0 {
+		add(pipelineStep{id: "environments", name: "Configured environments", after: []string{"access"}, run: func(r *stepRun) []error {
+			return r.c.SyncEnvironments(r.nwo, p.Environments, r.report)
+		}})
+	}
+
+	// Actions policy, variables, and secrets go first so pushed workflows
+	// never run without them.
+	bp := p.EffectiveBoilerplate()
+	if mode == modeFork {
+		bp = p.ForkBoilerplate()
+	}
+	// post_create hooks run in the boilerplate's working tree, or the
+	// published directory, so what they generate is pushed with it.
+	var postCreate [][]string
+	if mode == modeCreate || mode == modePublish {
+		postCreate = p.Hooks.PostCreate
+	}
+	pushAfter := []string{"contents", "actions-policy", "actions-variables", "actions-secrets"}
+	switch {
+	case mode == modePublish:
+		add(pipelineStep{id: "push", name: "Pushed local history", after: pushAfter, run: func(r *stepRun) []error {
+			if missing := missingBoilerplate(bp, r.dir); len(missing.Files) > 0 {
+				err := commitBoilerplate(r.dir, missing)
+				r.report(fmt.Sprintf("Added boilerplate files (%d)", len(missing.Files)), err)
+				if err != nil {
+					return []error{err}
+				}
+			}
+			if len(postCreate) > 0 {
+				err := runHooksAndCommit("post_create", postCreate, r.dir, createHookEnv("post_create", r.nwo, opts), r.reportLocalOutput)
+				if err != nil {
+					return []error{err}
+				}
+			}
+			err := pushLocalHistory(r.dir)
+			r.report("Pushed local history", err)
+			return errList(err)
+		}})
+	case mode != modeApply && (len(bp.Files) > 0 || len(postCreate) > 0):
+		add(pipelineStep{id: "boilerplate", name: "Pushed boilerplate files", after: pushAfter, run: func(r *stepRun) []error {
+			var prepare func(dir string) error
+			if len(postCreate) > 0 {
+				prepare = func(dir string) error {
+					return runHooks("post_create", postCreate, dir, createHookEnv("post_create", r.nwo, opts), r.reportLocalOutput)
+				}
+			}
+			sha, err := r.c.scaffoldAndPush(r.nwo, bp, opts.CloneDir, prepare)
+			r.scaffoldSHA = sha
+			r.report("Pushed boilerplate files", err)
+			return errList(err)
+		}})
+	}
+
+	// Workflows pushed to a fork are disabled until enabled one by one.
+	if mode == modeFork && p.Fork.SyncUpstream {
+		add(pipelineStep{id: "sync-workflow", name: "Enabled upstream sync workflow", after: []string{"boilerplate"}, run: func(r *stepRun) []error {
+			err := r.c.EnableWorkflow(r.nwo, "upstream-sync.yml")
+			r.report("Enabled upstream sync workflow", err)
+			return errList(err)
+		}})
+	}
+
+	// A legacy Pages source branch and the protected branch exist once the
+	// boilerplate or local history is pushed.
+	if p.Pages.Enabled {
+		add(pipelineStep{id: "pages", name: "Configured Pages", after: []string{"contents", "boilerplate", "push"}, run: func(r *stepRun) []error {
+			err := r.c.ConfigurePages(r.nwo, p.Pages)
+			r.report("Configured Pages", err)
+			return errList(err)
+		}})
+	}
+
+	if p.BranchProtection.Branch != "" {
+		add(pipelineStep{id: "branch-protection", name: "Set branch protection", after: []string{"contents", "boilerplate", "push"}, run: func(r *stepRun) []error {
+			err := r.c.SetBranchProtection(r.nwo, p.BranchProtection)
+			r.report("Set branch protection", err)
+			return errList(err)
+		}})
+	}
+
+	// The boilerplate step clones into the same directory when there is
+	// one, so this only clones when it didn't run.
+	if dir := opts.CloneDir; dir != "" {
+		add(pipelineStep{id: "clone", name: "Cloned repository", after: []string{"contents", "boilerplate"}, run: func(r *stepRun) []error {
+			err := r.c.cloneRepo(r.nwo, dir)
+			r.reportLocalOutput("Cloned repository to "+dir, "", err)
+			if err != nil || !p.Local.IsSet() {
+				return errList(err)
+			}
+			err = configureClone(dir, p.Local, config.NewRepoVars(r.nwo))
+			r.reportLocalOutput("Configured local clone", "", err)
+			if err == nil {
+				err = runHooks("post_clone", p.Local.PostClone, dir, hookEnv("post_clone", r.nwo, p.Hooks.PassEnv), r.reportLocalOutput)
+			}
+			return errList(err)
+		}})
+	}
+
+	// post_apply hooks wait for every other step, in the clone if there is
+	// one.
+	if cmds := p.Hooks.PostApply; mode == modeApply && len(cmds) > 0 {
+		after := make([]string, len(steps))
+		for i, s := range steps {
+			after[i] = s.id
+		}
+		add(pipelineStep{id: "post-apply", name: "Ran post_apply hooks", after: after, run: func(r *stepRun) []error {
+			return errList(runHooks("post_apply", cmds, opts.CloneDir, hookEnv("post_apply", r.nwo, p.Hooks.PassEnv), r.reportLocalOutput))
+		}})
+	}
+
+	return newPlan(steps)
+}
+
+// CreatePlan returns the steps CreateWithDefaults runs after creating the
+// repo, with their dependencies.
+func CreatePlan(opts CreateOpts) []PlanStep {
+	return describePlan(profileSteps(&opts, modeCreate))
+}
+
+// ApplyPlan returns the steps ApplyProfile runs, with their dependencies.
+func ApplyPlan(opts CreateOpts) []PlanStep {
+	return describePlan(profileSteps(&opts, modeApply))
+}
+
+// PublishPlan returns the steps PublishWithDefaults runs after creating the
+// repo, with their dependencies.
+func PublishPlan(opts CreateOpts) []PlanStep {
+	return describePlan(profileSteps(&opts, modePublish))
+}
+
+// ForkPlan returns the steps ForkWithDefaults runs after forking, with
+// their dependencies.
+func ForkPlan(opts CreateOpts) []PlanStep {
+	return describePlan(profileSteps(&opts, modeFork))
+}
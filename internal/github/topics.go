@@ -0,0 +1,78 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// mergeTopics returns the topics to set: desired alone when replacing,
+// otherwise existing followed by any desired topics not already present.
+func mergeTopics(existing, desired []string, replace bool) []string {
+	var merged []string
+	seen := make(map[string]bool)
+	add := func(names []string) {
+		for _, n := range names {
+			if !seen[n] {
+				seen[n] = true
+				merged = append(merged, n)
+			}
+		}
+	}
+	if !replace {
+		add(existing)
+	}
+	add(desired)
+	if merged == nil {
+		merged = []string{}
+	}
+	return merged
+}
+
+func (c *Client) ListTopics(nwo string) ([]string, error) {
+	out, err := c.api("GET", fmt.Sprintf("repos/%s/topics", nwo), nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing topics: %w", err)
+	}
+	var resp struct {
+		Names []string `json:"names"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		return nil, fmt.Errorf("parsing topics: %w", err)
+	}
+	return resp.Names, nil
+}
+
+func (c *Client) SetTopics(nwo string, names []string) error {
+	body := map[string]interface{}{"names": names}
+	if _, err := c.api("PUT", fmt.Sprintf("repos/%s/topics", nwo), body); err != nil {
+		return fmt.Errorf("setting topics: %w", err)
+	}
+	return nil
+}
+
+// SyncTopics applies the profile topics plus any extra topics to the repo.
+// Returns the number of topics the repo ends up with.
+func (c *Client) SyncTopics(nwo string, cfg config.TopicConfig, extra []string) (int, error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		return 0, fmt.Errorf("invalid nwo: %w", err)
+	}
+	desired := mergeTopics(cfg.Items, extra, false)
+	var existing []string
+	if !cfg.Replace {
+		var err error
+		existing, err = c.ListTopics(nwo)
+		if err != nil {
+			return 0, err
+		}
+	}
+	topics := mergeTopics(existing, desired, cfg.Replace)
+	if err := config.ValidateTopics(topics); err != nil {
+		return 0, err
+	}
+	if err := c.SetTopics(nwo, topics); err != nil {
+		return 0, err
+	}
+	return len(topics), nil
+}
@@ -0,0 +1,29 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeTopics(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		desired  []string
+		replace  bool
+		want     []string
+	}{
+		{"merge keeps existing", []string{"go"}, []string{"cli"}, false, []string{"go", "cli"}},
+		{"merge dedupes", []string{"go", "cli"}, []string{"cli", "tool"}, false, []string{"go", "cli", "tool"}},
+		{"replace drops existing", []string{"go"}, []string{"cli"}, true, []string{"cli"}},
+		{"replace with nothing clears", []string{"go"}, nil, true, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeTopics(tt.existing, tt.desired, tt.replace)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeTopics() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// webhookPayload builds the create/update body for a hook. The secret is
+// read from the environment variable named by SecretEnv.
+func webhookPayload(w config.Webhook) (map[string]interface{}, error) {
+	contentType := w.ContentType
+	if contentType == "" {
+		contentType = "json"
+	}
+	hookConfig := map[string]interface{}{
+		"url":          w.URL,
+		"content_type": contentType,
+		"insecure_ssl": "0",
+	}
+	if w.SecretEnv != "" {
+		secret, ok := os.LookupEnv(w.SecretEnv)
+		if !ok || secret == "" {
+			return nil, fmt.Errorf("webhook %s: environment variable %s is not set", w.URL, w.SecretEnv)
+		}
+		hookConfig["secret"] = secret
+	}
+	events := w.Events
+	if len(events) == 0 {
+		events = []string{"push"}
+	}
+	return map[string]interface{}{
+		"name":   "web",
+		"config": hookConfig,
+		"events": events,
+		"active": w.Active == nil || *w.Active,
+	}, nil
+}
+
+// listWebhooks returns the repo's hook IDs by URL. A URL can have more
+// than one hook.
+func (c *Client) listWebhooks(nwo string) (map[string][]int64, error) {
+	lines, err := c.apiList(fmt.Sprintf("repos/%s/hooks", nwo), `.[] | "\(.id) \(.config.url)"`)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhooks: %w", err)
+	}
+	byURL := make(map[string][]int64)
+	for _, line := range lines {
+		idStr, url, _ := strings.Cut(line, " ")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing webhook %q: %w", line, err)
+		}
+		byURL[url] = append(byURL[url], id)
+	}
+	return byURL, nil
+}
+
+// SyncWebhooks reconciles hooks by URL: listed hooks are created or updated
+// in place, and hooks marked remove are deleted. Hooks not mentioned in the
+// profile are left alone.
+func (c *Client) SyncWebhooks(nwo string, hooks []config.Webhook) (created, updated, removed int, errs []error) {
+	if err := config.ValidateNWO(nwo); err != nil {
+		errs = append(errs, fmt.Errorf("invalid nwo: %w", err))
+		return
+	}
+	byURL, err := c.listWebhooks(nwo)
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+	return c.applyWebhooks(nwo, byURL, hooks)
+}
+
+// applyWebhooks makes the changes SyncWebhooks needs given the repo's
+// existing hook IDs by URL.
+func (c *Client) applyWebhooks(nwo string, byURL map[string][]int64, hooks []config.Webhook) (created, updated, removed int, errs []error) {
+	for _, w := range hooks {
+		ids := byURL[w.URL]
+		if w.Remove {
+			for _, id := range ids {
+				if _, err := c.api("DELETE", fmt.Sprintf("repos/%s/hooks/%d", nwo, id), nil); err != nil {
+					errs = append(errs, fmt.Errorf("removing webhook %s: %w", w.URL, err))
+				} else {
+					removed++
+				}
+			}
+			continue
+		}
+
+		body, err := webhookPayload(w)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(ids) == 0 {
+			if _, err := c.api("POST", fmt.Sprintf("repos/%s/hooks", nwo), body); err != nil {
+				errs = append(errs, fmt.Errorf("creating webhook %s: %w", w.URL, err))
+			} else {
+				created++
+			}
+			continue
+		}
+		delete(body, "name")
+		for _, id := range ids {
+			if _, err := c.api("PATCH", fmt.Sprintf("repos/%s/hooks/%d", nwo, id), body); err != nil {
+				errs = append(errs, fmt.Errorf("updating webhook %s: %w", w.URL, err))
+			} else {
+				updated++
+			}
+		}
+	}
+	return
+}
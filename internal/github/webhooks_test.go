@@ -0,0 +1,126 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestWebhookPayload_Defaults(t *testing.T) {
+	body, err := webhookPayload(config.Webhook{URL: "https://ci.example.com/hook"})
+	if err != nil {
+		t.Fatalf("webhookPayload: %v", err)
+	}
+	cfg := body["config"].(map[string]interface{})
+	if cfg["content_type"] != "json" {
+		t.Errorf("content_type = %v, want json", cfg["content_type"])
+	}
+	if _, ok := cfg["secret"]; ok {
+		t.Error("secret should be omitted without secret_env")
+	}
+	if body["active"] != true {
+		t.Errorf("active = %v, want true", body["active"])
+	}
+	if events := body["events"].([]string); len(events) != 1 || events[0] != "push" {
+		t.Errorf("events = %v, want [push]", events)
+	}
+}
+
+func TestWebhookPayload_MissingSecret(t *testing.T) {
+	_, err := webhookPayload(config.Webhook{URL: "https://ci.example.com/hook", SecretEnv: "GH_MINT_TEST_UNSET_HOOK_SECRET"})
+	if err == nil {
+		t.Error("expected error for unset secret env var")
+	}
+}
+
+func TestApplyWebhooks_FakeEndpoint(t *testing.T) {
+	t.Setenv("CHATOPS_SECRET", "s3cret")
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		switch {
+		case r.Method == "POST":
+			return http.StatusCreated, `{"id": 4}`
+		case r.Method == "PATCH":
+			return http.StatusOK, `{}`
+		case r.Method == "DELETE":
+			return http.StatusNoContent, ""
+		}
+		return http.StatusNotFound, ""
+	})
+
+	f := false
+	hooks := []config.Webhook{
+		{URL: "https://chatops.example.com/github", Events: []string{"push", "pull_request"}, SecretEnv: "CHATOPS_SECRET"},
+		{URL: "https://ci.example.com/dispatch", Events: []string{"push"}, Active: &f},
+		{URL: "https://old.example.com/hook", Remove: true},
+	}
+	existing := map[string][]int64{
+		"https://ci.example.com/dispatch": {1},
+		"https://old.example.com/hook":    {2},
+		"https://other.example.com/hook":  {3},
+	}
+	created, updated, removed, errs := c.applyWebhooks("acme/widget", existing, hooks)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if created != 1 || updated != 1 || removed != 1 {
+		t.Errorf("created/updated/removed = %d/%d/%d, want 1/1/1", created, updated, removed)
+	}
+
+	reqs := api.recorded()
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 requests, got %d: %+v", len(reqs), reqs)
+	}
+
+	var post map[string]interface{}
+	if reqs[0].Method != "POST" || reqs[0].Path != "/repos/acme/widget/hooks" {
+		t.Fatalf("create request = %+v", reqs[0])
+	}
+	if err := json.Unmarshal([]byte(reqs[0].Body), &post); err != nil {
+		t.Fatal(err)
+	}
+	hookCfg := post["config"].(map[string]interface{})
+	if hookCfg["url"] != "https://chatops.example.com/github" || hookCfg["secret"] != "s3cret" {
+		t.Errorf("create config = %v", hookCfg)
+	}
+	if post["name"] != "web" {
+		t.Errorf("create name = %v, want web", post["name"])
+	}
+
+	var patch map[string]interface{}
+	if reqs[1].Method != "PATCH" || reqs[1].Path != "/repos/acme/widget/hooks/1" {
+		t.Fatalf("update request = %+v", reqs[1])
+	}
+	if err := json.Unmarshal([]byte(reqs[1].Body), &patch); err != nil {
+		t.Fatal(err)
+	}
+	if patch["active"] != false {
+		t.Errorf("update active = %v, want false", patch["active"])
+	}
+	if _, ok := patch["name"]; ok {
+		t.Error("update body should not include name")
+	}
+
+	if reqs[2].Method != "DELETE" || reqs[2].Path != "/repos/acme/widget/hooks/2" {
+		t.Errorf("remove request = %+v", reqs[2])
+	}
+}
+
+func TestApplyWebhooks_Idempotent(t *testing.T) {
+	c, api := newFakeAPIClient(t, func(r recordedRequest) (int, string) {
+		return http.StatusOK, `{}`
+	})
+	hooks := []config.Webhook{{URL: "https://ci.example.com/dispatch"}}
+	for i := 0; i < 2; i++ {
+		created, updated, _, errs := c.applyWebhooks("acme/widget", map[string][]int64{"https://ci.example.com/dispatch": {7}}, hooks)
+		if len(errs) > 0 || created != 0 || updated != 1 {
+			t.Fatalf("run %d: created=%d updated=%d errs=%v", i, created, updated, errs)
+		}
+	}
+	for _, r := range api.recorded() {
+		if r.Method == "POST" {
+			t.Errorf("existing hook should never be re-created: %+v", r)
+		}
+	}
+}
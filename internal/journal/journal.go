@@ -0,0 +1,319 @@
+// Package journal persists the outcome of each step of a create run so
+// that a failed run can be resumed without repeating finished steps.
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var runIDPattern = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{6}$`)
+
+// Status is the outcome of a journaled step.
+type Status string
+
+const (
+	Pending   Status = "pending" // planned but not started
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+	Skipped   Status = "skipped" // not run because a step it waits for failed
+)
+
+// Step is the last recorded outcome of one step; Status is empty while a
+// step that saved output has not finished. Output holds whatever the
+// step needs to pick up where it left off, such as issues already opened.
+type Step struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Status  Status          `json:"status"`
+	Error   string          `json:"error,omitempty"`
+	Output  json.RawMessage `json:"output,omitempty"`
+	Updated time.Time       `json:"updated"`
+}
+
+// Run records the create options and step outcomes of one run. It is
+// rewritten to disk after every change.
+type Run struct {
+	ID          string    `json:"id"`
+	Started     time.Time `json:"started"`
+	Profile     string    `json:"profile"`
+	Name        string    `json:"name"`
+	Owner       string    `json:"owner,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Public      bool      `json:"public"`
+	Topics      []string  `json:"topics,omitempty"`
+	Homepage    string    `json:"homepage,omitempty"`
+	Template    string    `json:"template,omitempty"`
+	CloneDir    string    `json:"clone_dir,omitempty"`
+	NWO         string    `json:"nwo,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	Adopted     bool      `json:"adopted,omitempty"` // the repo already existed
+	Steps       []*Step   `json:"steps"`
+	// Finished is set when a run stops with steps left to retry. A run
+	// without it was interrupted.
+	Finished *time.Time `json:"finished,omitempty"`
+
+	path string
+}
+
+// Dir returns the directory runs are kept in: $XDG_STATE_HOME/gh-mint/runs,
+// falling back to ~/.local/state/gh-mint/runs.
+func Dir() (string, error) {
+	if state := os.Getenv("XDG_STATE_HOME"); state != "" {
+		return filepath.Join(state, "gh-mint", "runs"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding state directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "gh-mint", "runs"), nil
+}
+
+func newID(now time.Time) (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return now.Format("20060102-150405") + "-" + hex.EncodeToString(b), nil
+}
+
+// ValidateID checks a run ID so it can't be used to escape the runs dir.
+func ValidateID(id string) error {
+	if !runIDPattern.MatchString(id) {
+		return fmt.Errorf("run id %q is not valid (expected e.g. 20250102-150405-a1b2c3)", id)
+	}
+	return nil
+}
+
+// New starts a run that will be saved in dir. The caller fills in the
+// create options; the run is first written when a step is recorded.
+func New(dir string) (*Run, error) {
+	now := time.Now()
+	id, err := newID(now)
+	if err != nil {
+		return nil, fmt.Errorf("generating run id: %w", err)
+	}
+	r := &Run{ID: id, Started: now.UTC(), path: filepath.Join(dir, id+".json")}
+	return r, nil
+}
+
+// Load reads a run from dir.
+func Load(dir, id string) (*Run, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, id+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("run %s not found", id)
+		}
+		return nil, fmt.Errorf("reading run %s: %w", id, err)
+	}
+	var r Run
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing run %s: %w", id, err)
+	}
+	r.path = path
+	return &r, nil
+}
+
+// List returns the runs in dir, newest first. Unreadable files are skipped.
+func List(dir string) ([]*Run, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing runs: %w", err)
+	}
+	var runs []*Run
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || ValidateID(id) != nil {
+			continue
+		}
+		if r, err := Load(dir, id); err == nil {
+			runs = append(runs, r)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].ID > runs[j].ID })
+	return runs, nil
+}
+
+// Save writes the run with owner-only permissions.
+func (r *Run) Save() error {
+	if r.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding run: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing run: %w", err)
+	}
+	return os.Rename(tmp, r.path)
+}
+
+func (r *Run) step(id string) *Step {
+	for _, s := range r.Steps {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// Succeeded reports whether the step has already completed.
+func (r *Run) Succeeded(id string) bool {
+	s := r.step(id)
+	return s != nil && s.Status == Succeeded
+}
+
+// Attempted reports whether the step has run before, whatever the outcome.
+func (r *Run) Attempted(id string) bool {
+	s := r.step(id)
+	return s != nil && s.Status != Pending && s.Status != Skipped
+}
+
+// Expect records steps that are about to run as pending, unless they
+// already have an outcome, and saves the run. A run that is interrupted
+// then still lists the steps that never ran, and isn't marked finished
+// until Finish is called again.
+func (r *Run) Expect(ids, names []string) error {
+	r.Finished = nil
+	for i, id := range ids {
+		if r.step(id) == nil {
+			r.Steps = append(r.Steps, &Step{ID: id, Name: names[i], Status: Pending, Updated: time.Now().UTC()})
+		}
+	}
+	return r.Save()
+}
+
+// Record stores a step's outcome and saves the run.
+func (r *Run) Record(id, name string, err error) error {
+	s := r.stepFor(id, name)
+	s.Status = Succeeded
+	s.Error = ""
+	if err != nil {
+		s.Status = Failed
+		s.Error = err.Error()
+	}
+	return r.Save()
+}
+
+// Skip records that a step didn't run, and why, and saves the run.
+func (r *Run) Skip(id, name, reason string) error {
+	s := r.stepFor(id, name)
+	s.Status = Skipped
+	s.Error = reason
+	return r.Save()
+}
+
+func (r *Run) stepFor(id, name string) *Step {
+	s := r.step(id)
+	if s == nil {
+		s = &Step{ID: id}
+		r.Steps = append(r.Steps, s)
+	}
+	s.Name = name
+	s.Updated = time.Now().UTC()
+	return s
+}
+
+// SetOutput stores v as the step's output and saves the run.
+func (r *Run) SetOutput(id string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding %s output: %w", id, err)
+	}
+	s := r.step(id)
+	if s == nil {
+		s = &Step{ID: id}
+		r.Steps = append(r.Steps, s)
+	}
+	s.Output = data
+	if s.Status == Pending || s.Status == Skipped {
+		s.Status = "" // running
+	}
+	s.Updated = time.Now().UTC()
+	return r.Save()
+}
+
+// Output decodes the step's saved output into v. It reports false when the
+// step has none.
+func (r *Run) Output(id string, v interface{}) (bool, error) {
+	s := r.step(id)
+	if s == nil || len(s.Output) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(s.Output, v); err != nil {
+		return false, fmt.Errorf("decoding %s output: %w", id, err)
+	}
+	return true, nil
+}
+
+// Unfinished returns the steps that haven't succeeded: failed, skipped,
+// never started, or interrupted.
+func (r *Run) Unfinished() []*Step {
+	var steps []*Step
+	for _, s := range r.Steps {
+		if s.Status != Succeeded {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// Resumable reports whether the run has anything left to retry: steps that
+// didn't succeed, or no record of finishing at all. A rolled-back run has
+// nothing left to work on.
+func (r *Run) Resumable() bool {
+	if r.Succeeded("rollback") {
+		return false
+	}
+	return r.Finished == nil || len(r.Unfinished()) > 0
+}
+
+// Finish marks the end of a run. A run with nothing left to resume, done,
+// is deleted, so finished runs don't pile up; otherwise it is marked
+// finished and kept for resume. A run that never recorded a step was never
+// saved and stays that way.
+func (r *Run) Finish(done bool) error {
+	if r.path == "" || len(r.Steps) == 0 {
+		return nil
+	}
+	if done {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing run: %w", err)
+		}
+		return nil
+	}
+	now := time.Now().UTC()
+	r.Finished = &now
+	return r.Save()
+}
+
+// Failed returns the steps whose last outcome was a failure.
+func (r *Run) Failed() []*Step {
+	var failed []*Step
+	for _, s := range r.Steps {
+		if s.Status == Failed {
+			failed = append(failed, s)
+		}
+	}
+	return failed
+}
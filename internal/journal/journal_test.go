@@ -0,0 +1,145 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_RecordAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	r, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := ValidateID(r.ID); err != nil {
+		t.Fatalf("generated id: %v", err)
+	}
+	r.Profile, r.Name, r.NWO = "oss", "widget", "acme/widget"
+
+	if err := r.Record("create", "Created repository", nil); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	r.Record("labels", "Synced labels", errors.New("HTTP 502"))
+	r.SetOutput("seed", map[string]int{"v0.1": 1})
+
+	info, err := os.Stat(filepath.Join(dir, r.ID+".json"))
+	if err != nil {
+		t.Fatalf("run not saved: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := Load(dir, r.ID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.NWO != "acme/widget" || loaded.Profile != "oss" {
+		t.Errorf("loaded = %+v", loaded)
+	}
+	if !loaded.Succeeded("create") || loaded.Succeeded("labels") || !loaded.Attempted("labels") || loaded.Attempted("pages") {
+		t.Error("step states did not round-trip")
+	}
+	failed := loaded.Failed()
+	if len(failed) != 1 || failed[0].ID != "labels" || failed[0].Error != "HTTP 502" {
+		t.Errorf("Failed() = %+v", failed)
+	}
+	var seed map[string]int
+	if ok, err := loaded.Output("seed", &seed); !ok || err != nil || seed["v0.1"] != 1 {
+		t.Errorf("Output = %v, %v, %v", seed, ok, err)
+	}
+	if ok, _ := loaded.Output("pages", &seed); ok {
+		t.Error("expected no output for a step that never ran")
+	}
+
+	loaded.Record("labels", "Synced labels", nil)
+	if !loaded.Succeeded("labels") || len(loaded.Failed()) != 0 {
+		t.Error("retried step should replace the failure")
+	}
+}
+
+func TestLoad_RejectsBadID(t *testing.T) {
+	for _, id := range []string{"", "../config", "20250102-150405-a1b2c3/../x", "latest"} {
+		if _, err := Load(t.TempDir(), id); err == nil {
+			t.Errorf("Load(%q) should fail", id)
+		}
+	}
+}
+
+func TestList_NewestFirst(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []string{"20250101-090000-aaaaaa", "20250301-090000-bbbbbb", "20250201-090000-cccccc"} {
+		r := &Run{ID: id, path: filepath.Join(dir, id+".json")}
+		if err := r.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0600)
+
+	runs, err := List(dir)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(runs) != 3 || runs[0].ID != "20250301-090000-bbbbbb" || runs[2].ID != "20250101-090000-aaaaaa" {
+		t.Errorf("runs = %v", runs)
+	}
+
+	if runs, err := List(filepath.Join(dir, "missing")); err != nil || runs != nil {
+		t.Errorf("List(missing) = %v, %v", runs, err)
+	}
+}
+
+func TestRun_ExpectAndFinish(t *testing.T) {
+	dir := t.TempDir()
+	r, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Finish(false); err != nil {
+		t.Fatalf("Finish before any step: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, r.ID+".json")); !os.IsNotExist(err) {
+		t.Error("a run with no steps should never be written")
+	}
+
+	r.Record("create", "Created repository", nil)
+	if err := r.Expect([]string{"create", "labels", "seed"}, []string{"Created repository", "Synced labels", "Seeded issues"}); err != nil {
+		t.Fatalf("Expect: %v", err)
+	}
+	if !r.Succeeded("create") || r.Attempted("labels") || len(r.Unfinished()) != 2 {
+		t.Errorf("steps = %+v, want labels and seed pending", r.Unfinished())
+	}
+	if !r.Resumable() {
+		t.Error("a run that never finished should be resumable")
+	}
+
+	r.SetOutput("seed", []string{"Roadmap"})
+	if !r.Attempted("seed") {
+		t.Error("a step that saved output has started")
+	}
+	r.Skip("labels", "Synced labels", "skipped because Created repository failed")
+	r.Record("seed", "Seeded issues", nil)
+	if err := r.Finish(false); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	loaded, err := Load(dir, r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Finished == nil || !loaded.Resumable() || len(loaded.Unfinished()) != 1 || loaded.Unfinished()[0].Status != Skipped {
+		t.Errorf("finished run = %+v, want resumable with labels skipped", loaded)
+	}
+
+	loaded.Record("labels", "Synced labels", nil)
+	if loaded.Resumable() {
+		t.Error("a finished run with every step done has nothing to resume")
+	}
+	if err := loaded.Finish(true); err != nil {
+		t.Fatalf("Finish(done): %v", err)
+	}
+	if _, err := Load(dir, r.ID); err == nil {
+		t.Error("a done run should be deleted")
+	}
+}
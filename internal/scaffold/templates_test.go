@@ -2,6 +2,7 @@ package scaffold
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -44,3 +45,29 @@ func TestResolveTemplate_PathTraversal(t *testing.T) {
 		t.Error("expected error for path traversal")
 	}
 }
+
+func TestResolveTemplate_PagesWorkflow(t *testing.T) {
+	content, err := ResolveTemplate("pages.yml", "")
+	if err != nil {
+		t.Fatalf("ResolveTemplate: %v", err)
+	}
+	if !strings.Contains(string(content), "actions/deploy-pages") {
+		t.Error("pages.yml should deploy with actions/deploy-pages")
+	}
+	if strings.Contains(string(content), "branches: [main]") || !strings.Contains(string(content), "github.event.repository.default_branch") {
+		t.Error("pages.yml should deploy from the repo's default branch, not a fixed one")
+	}
+	if !strings.Contains(string(content), `paths: ["docs/**"]`) {
+		t.Error("pages.yml should only run on push once docs/ exists")
+	}
+}
+
+func TestResolveTemplate_UpstreamSyncWorkflow(t *testing.T) {
+	content, err := ResolveTemplate("upstream-sync.yml", "")
+	if err != nil {
+		t.Fatalf("ResolveTemplate: %v", err)
+	}
+	if !strings.Contains(string(content), "gh repo sync") {
+		t.Error("upstream-sync.yml should sync with gh repo sync")
+	}
+}
@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Seal encrypts message for a Curve25519 public key using a libsodium
+// sealed box (crypto_box_seal), the format GitHub expects for Actions,
+// environment, and Dependabot secrets. randReader supplies the ephemeral
+// key; pass nil to use crypto/rand.
+func Seal(publicKey []byte, message []byte, randReader io.Reader) ([]byte, error) {
+	if len(publicKey) != 32 {
+		return nil, fmt.Errorf("public key must be 32 bytes, got %d", len(publicKey))
+	}
+	if randReader == nil {
+		randReader = rand.Reader
+	}
+	var pk [32]byte
+	copy(pk[:], publicKey)
+	sealed, err := box.SealAnonymous(nil, message, &pk, randReader)
+	if err != nil {
+		return nil, fmt.Errorf("sealing secret: %w", err)
+	}
+	return sealed, nil
+}
+
+// SealBase64 takes the base64 public key returned by the GitHub API and
+// returns the base64 ciphertext to upload as encrypted_value.
+func SealBase64(publicKey string, value string) (string, error) {
+	pk, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("decoding public key: %w", err)
+	}
+	sealed, err := Seal(pk, []byte(value), nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
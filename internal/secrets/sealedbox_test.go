@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Fixed recipient keypair so the test doesn't depend on randomness.
+func testKeyPair(t *testing.T) (*[32]byte, *[32]byte) {
+	t.Helper()
+	seed := bytes.Repeat([]byte{0x42}, 32)
+	pub, priv, err := box.GenerateKey(bytes.NewReader(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub, priv
+}
+
+func TestSeal_RoundTrip(t *testing.T) {
+	pub, priv := testKeyPair(t)
+	sealed, err := Seal(pub[:], []byte("hunter2"), nil)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	// 32-byte ephemeral public key + 16-byte Poly1305 tag
+	if len(sealed) != len("hunter2")+box.AnonymousOverhead {
+		t.Errorf("sealed length = %d, want %d", len(sealed), len("hunter2")+box.AnonymousOverhead)
+	}
+	opened, ok := box.OpenAnonymous(nil, sealed, pub, priv)
+	if !ok {
+		t.Fatal("OpenAnonymous failed")
+	}
+	if string(opened) != "hunter2" {
+		t.Errorf("opened = %q, want %q", opened, "hunter2")
+	}
+}
+
+func TestSeal_Deterministic(t *testing.T) {
+	pub, _ := testKeyPair(t)
+	eph := bytes.Repeat([]byte{0x07}, 32)
+	a, err := Seal(pub[:], []byte("value"), bytes.NewReader(eph))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Seal(pub[:], []byte("value"), bytes.NewReader(eph))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("expected identical ciphertext for identical ephemeral key")
+	}
+}
+
+func TestSeal_BadKeyLength(t *testing.T) {
+	if _, err := Seal([]byte("short"), []byte("value"), nil); err == nil {
+		t.Error("expected error for short public key")
+	}
+}
+
+func TestSealBase64(t *testing.T) {
+	pub, priv := testKeyPair(t)
+	out, err := SealBase64(base64.StdEncoding.EncodeToString(pub[:]), "s3cret")
+	if err != nil {
+		t.Fatalf("SealBase64: %v", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(out)
+	if err != nil {
+		t.Fatalf("output is not base64: %v", err)
+	}
+	opened, ok := box.OpenAnonymous(nil, sealed, pub, priv)
+	if !ok || string(opened) != "s3cret" {
+		t.Errorf("round trip failed: ok=%v opened=%q", ok, opened)
+	}
+}
@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+// maxSecretSize is GitHub's limit on a secret value.
+const maxSecretSize = 48 * 1024
+
+// Resolve reads a secret value from its source. Trailing newlines are
+// trimmed so that files and command output behave like env vars.
+func Resolve(src config.SecretSource) (string, error) {
+	if err := config.ValidateSecretSource(src); err != nil {
+		return "", err
+	}
+
+	var value string
+	switch {
+	case src.Env != "":
+		v, ok := os.LookupEnv(src.Env)
+		if !ok || v == "" {
+			return "", fmt.Errorf("environment variable %s is not set", src.Env)
+		}
+		value = v
+	case src.File != "":
+		path, err := expandHome(src.File)
+		if err != nil {
+			return "", err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading secret file: %w", err)
+		}
+		value = string(data)
+	default:
+		cmd := exec.Command(src.Command[0], src.Command[1:]...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("running %s: %w\n%s", src.Command[0], err, stderr.String())
+		}
+		value = string(out)
+	}
+
+	value = strings.TrimRight(value, "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("secret from %s is empty", src.Kind())
+	}
+	if len(value) > maxSecretSize {
+		return "", fmt.Errorf("secret from %s exceeds %d bytes", src.Kind(), maxSecretSize)
+	}
+	return value, nil
+}
+
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
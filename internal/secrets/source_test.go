@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ggfevans/gh-mint/internal/config"
+)
+
+func TestResolve_Env(t *testing.T) {
+	t.Setenv("GH_MINT_TEST_SECRET", "from-env")
+	v, err := Resolve(config.SecretSource{Env: "GH_MINT_TEST_SECRET"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if v != "from-env" {
+		t.Errorf("value = %q", v)
+	}
+}
+
+func TestResolve_EnvUnset(t *testing.T) {
+	if _, err := Resolve(config.SecretSource{Env: "GH_MINT_TEST_UNSET_SECRET"}); err == nil {
+		t.Error("expected error for unset env var")
+	}
+}
+
+func TestResolve_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	v, err := Resolve(config.SecretSource{File: path})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if v != "from-file" {
+		t.Errorf("value = %q, want trailing newline trimmed", v)
+	}
+}
+
+func TestResolve_Command(t *testing.T) {
+	v, err := Resolve(config.SecretSource{Command: []string{"echo", "from-command"}})
+	if err != nil {
+		t.Skipf("echo unavailable: %v", err)
+	}
+	if v != "from-command" {
+		t.Errorf("value = %q", v)
+	}
+}
+
+func TestResolve_NoSource(t *testing.T) {
+	if _, err := Resolve(config.SecretSource{}); err == nil {
+		t.Error("expected error when no source is set")
+	}
+}
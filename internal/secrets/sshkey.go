@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// GenerateSSHKey creates an ed25519 keypair and returns the public key in
+// authorized_keys format and the private key as an OpenSSH PEM block.
+func GenerateSSHKey(comment string, randReader io.Reader) (publicKey string, privateKey []byte, err error) {
+	if randReader == nil {
+		randReader = rand.Reader
+	}
+	pub, priv, err := ed25519.GenerateKey(randReader)
+	if err != nil {
+		return "", nil, fmt.Errorf("generating ed25519 key: %w", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return "", nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return "", nil, fmt.Errorf("encoding private key: %w", err)
+	}
+	publicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	if comment != "" {
+		publicKey += " " + comment
+	}
+	return publicKey, pem.EncodeToMemory(block), nil
+}
+
+// ReadPublicKey reads an SSH public key in authorized_keys format and
+// checks that it parses.
+func ReadPublicKey(path string) (string, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading public key: %w", err)
+	}
+	if _, _, _, _, err := ssh.ParseAuthorizedKey(data); err != nil {
+		return "", fmt.Errorf("%s is not an SSH public key: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// WritePrivateKey writes a private key with owner-only permissions. It
+// refuses to replace an existing file.
+func WritePrivateKey(path string, key []byte) error {
+	path, err := expandHome(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating key directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("private key %s already exists", path)
+		}
+		return fmt.Errorf("writing private key: %w", err)
+	}
+	if _, err := f.Write(key); err != nil {
+		f.Close()
+		return fmt.Errorf("writing private key: %w", err)
+	}
+	return f.Close()
+}
+
+// RemovePrivateKey deletes a key WritePrivateKey wrote, for when it turns
+// out not to be needed. A key that is already gone is not an error.
+func RemovePrivateKey(path string) error {
+	path, err := expandHome(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing private key: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -19,6 +20,11 @@ type ProgressModel struct {
 	done   bool
 	url    string
 	err    error
+
+	// exists is the repo that was found to already exist; adopt applies the
+	// profile to it on the next run.
+	exists string
+	adopt  bool
 }
 
 func NewProgressModel(styles *Styles, create CreateModel) ProgressModel {
@@ -45,6 +51,7 @@ func (m ProgressModel) startCreation() tea.Cmd {
 			Public:      m.create.IsPublic(),
 			Profile:     m.create.Profile(),
 			OnProgress:  func(s ghclient.StepStatus) {},
+			Adopt:       m.adopt,
 		}
 
 		url, err := client.CreateWithDefaults(opts)
@@ -58,7 +65,16 @@ func (m ProgressModel) Update(msg tea.Msg) (ProgressModel, tea.Cmd) {
 		m.done = true
 		m.url = msg.url
 		m.err = msg.err
+		var exists *ghclient.RepoExistsError
+		if errors.As(msg.err, &exists) {
+			m.exists = exists.NWO
+		}
 		return m, nil
+	case tea.KeyMsg:
+		if m.exists != "" && msg.String() == "a" {
+			m.done, m.err, m.exists, m.adopt = false, nil, "", true
+			return m, m.startCreation()
+		}
 	}
 	return m, nil
 }
@@ -66,12 +82,20 @@ func (m ProgressModel) Update(msg tea.Msg) (ProgressModel, tea.Cmd) {
 func (m ProgressModel) View() string {
 	var b strings.Builder
 
-	b.WriteString(m.styles.Header.Render("Creating repository..."))
+	header := "Creating repository..."
+	if m.adopt {
+		header = "Applying profile to existing repository..."
+	}
+	b.WriteString(m.styles.Header.Render(header))
 	b.WriteString("\n\n")
 
 	if !m.done {
 		b.WriteString("  Working...\n")
 	} else {
+		if m.exists != "" {
+			b.WriteString(fmt.Sprintf("  Repository %s already exists.\n\n  Press a to apply the profile to it, or q to exit.\n", m.exists))
+			return b.String()
+		}
 		if m.err != nil {
 			b.WriteString(m.styles.Error.Render(fmt.Sprintf("  Error: %s\n", m.err)))
 		}